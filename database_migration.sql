@@ -0,0 +1,298 @@
+-- Refresh tokens (solo se guarda el hash SHA-256 del token)
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    family_id VARCHAR(64) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    rotated_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uq_refresh_tokens_hash (token_hash),
+    KEY idx_refresh_tokens_user (user_id),
+    KEY idx_refresh_tokens_family (family_id),
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Roles y permisos
+CREATE TABLE IF NOT EXISTS roles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NULL,
+    UNIQUE KEY uq_roles_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_id, permission),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INT NOT NULL,
+    role_id INT NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
+
+INSERT IGNORE INTO roles (name, description) VALUES
+    ('admin', 'Acceso total a la administración'),
+    ('catalog-editor', 'Gestiona productos y categorías'),
+    ('moderator', 'Modera comentarios'),
+    ('customer', 'Cliente registrado');
+
+INSERT IGNORE INTO role_permissions (role_id, permission)
+SELECT r.id, p.permission
+FROM roles r
+JOIN (
+    SELECT 'admin' AS role_name, 'users:read' AS permission
+    UNION ALL SELECT 'admin', 'users:write'
+    UNION ALL SELECT 'admin', 'roles:manage'
+    UNION ALL SELECT 'admin', 'products:write'
+    UNION ALL SELECT 'admin', 'categories:write'
+    UNION ALL SELECT 'admin', 'comments:write'
+    UNION ALL SELECT 'admin', 'comments:moderate'
+    UNION ALL SELECT 'catalog-editor', 'products:write'
+    UNION ALL SELECT 'catalog-editor', 'categories:write'
+    UNION ALL SELECT 'catalog-editor', 'comments:write'
+    UNION ALL SELECT 'moderator', 'users:read'
+    UNION ALL SELECT 'moderator', 'comments:write'
+    UNION ALL SELECT 'moderator', 'comments:moderate'
+    UNION ALL SELECT 'customer', 'comments:write'
+) p ON p.role_name = r.name;
+
+-- Los usuarios existentes reciben el rol de cliente
+INSERT IGNORE INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM user u JOIN roles r ON r.name = 'customer';
+
+-- Para promover al primer administrador:
+-- INSERT IGNORE INTO user_roles (user_id, role_id) SELECT <id_usuario>, id FROM roles WHERE name = 'admin';
+
+-- Motivo y fecha de desactivación de cuentas
+ALTER TABLE user
+    ADD COLUMN deactivation_reason VARCHAR(255) NULL,
+    ADD COLUMN deactivated_at DATETIME NULL,
+    ADD COLUMN deactivated_by INT NULL;
+
+-- Unicidad de username y email (respaldo de la validación en la aplicación)
+ALTER TABLE user
+    ADD UNIQUE KEY uq_user_username (userName),
+    ADD UNIQUE KEY uq_user_email (email);
+
+-- Intentos fallidos de login (LOGIN_ATTEMPT_STORE=mysql)
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attempt_key VARCHAR(191) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    lock_count INT NOT NULL DEFAULT 0,
+    last_failure_at DATETIME NULL,
+    locked_until DATETIME NULL
+);
+
+-- Tokens de restablecimiento de contraseña (solo se guarda el hash)
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uq_password_reset_hash (token_hash),
+    KEY idx_password_reset_user (user_id),
+    CONSTRAINT fk_password_reset_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Verificación de email: las cuentas nuevas quedan pendientes hasta confirmar
+ALTER TABLE user ADD COLUMN email_verified_at DATETIME NULL;
+
+-- Las cuentas existentes se consideran verificadas
+UPDATE user SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uq_email_verification_hash (token_hash),
+    KEY idx_email_verification_user (user_id, created_at),
+    CONSTRAINT fk_email_verification_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Autenticación en dos pasos (TOTP) y códigos de recuperación
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id INT PRIMARY KEY,
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at DATETIME NULL,
+    CONSTRAINT fk_two_factor_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    KEY idx_recovery_codes_user (user_id, code_hash),
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- API keys para integraciones entre servidores (solo se guarda el hash)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix CHAR(8) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT NOT NULL,
+    expires_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    last_used_ip VARCHAR(45) NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uq_api_keys_hash (key_hash),
+    KEY idx_api_keys_user (user_id),
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Sesiones de servidor creadas en cada login; el id coincide con la familia de refresh tokens
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id VARCHAR(32) PRIMARY KEY,
+    user_id INT NOT NULL,
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    KEY idx_user_sessions_user (user_id, revoked_at),
+    CONSTRAINT fk_user_sessions_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Derecho al olvido: las cuentas se anonimizan en lugar de borrarse
+ALTER TABLE user ADD COLUMN anonymized_at DATETIME NULL;
+
+-- Bitácora de auditoría; sin claves foráneas para que sobreviva a cualquier cambio en los usuarios
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NOT NULL,
+    target_user_id INT NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    details TEXT NULL,
+    ip VARCHAR(45) NULL,
+    created_at DATETIME NOT NULL,
+    KEY idx_audit_log_actor (actor_id, created_at),
+    KEY idx_audit_log_target (target_user_id, created_at)
+);
+
+-- Búsqueda de comentarios por autor para la exportación y la anonimización
+CREATE INDEX idx_comments_user_name ON comments (user_name);
+
+-- Suplantación de usuarios: permiso exclusivo de los administradores
+INSERT IGNORE INTO role_permissions (role_id, permission)
+SELECT id, 'users:impersonate' FROM roles WHERE name = 'admin';
+
+-- Los hashes argon2id ocupan más que los de bcrypt
+ALTER TABLE user MODIFY COLUMN password VARCHAR(255) NOT NULL;
+
+-- Identidades OpenID Connect vinculadas a los usuarios
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    last_login_at DATETIME NULL,
+    UNIQUE KEY uq_user_identities_subject (issuer, subject),
+    KEY idx_user_identities_user (user_id),
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Solicitudes de autorización OIDC en curso (state, nonce y verificador PKCE)
+CREATE TABLE IF NOT EXISTS oidc_auth_requests (
+    state_hash CHAR(64) PRIMARY KEY,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    link_user_id INT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT fk_oidc_auth_requests_user FOREIGN KEY (link_user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- Perfil del usuario: nombre visible, contacto, preferencias regionales y avatar
+ALTER TABLE user
+    ADD COLUMN display_name VARCHAR(100) NULL,
+    ADD COLUMN phone VARCHAR(20) NULL,
+    ADD COLUMN locale VARCHAR(35) NULL,
+    ADD COLUMN timezone VARCHAR(64) NULL,
+    ADD COLUMN avatar_key VARCHAR(255) NULL,
+    ADD COLUMN avatar_url VARCHAR(255) NULL;
+
+-- Índices para los filtros y el orden del listado de productos
+CREATE INDEX idx_products_category ON products (category);
+CREATE INDEX idx_products_price ON products (price);
+CREATE INDEX idx_products_name ON products (name);
+CREATE INDEX idx_comments_product_rating ON comments (product_id, rating);
+
+-- Productos enlazados a la tabla de categorías: cada nombre de categoría usado por un
+-- producto se convierte en una fila de categories (creándola si no existe) y se
+-- sustituye por su ID. Los productos sin categoría quedan en "Sin Categoría".
+UPDATE products SET category = 'Sin Categoría' WHERE TRIM(COALESCE(category, '')) = '';
+
+INSERT INTO categories (name, description, is_active)
+SELECT DISTINCT TRIM(p.category), '', TRUE
+FROM products p
+LEFT JOIN categories c ON c.name = TRIM(p.category)
+WHERE c.id IS NULL;
+
+ALTER TABLE products ADD COLUMN category_id INT NULL AFTER price;
+
+UPDATE products p
+JOIN categories c ON c.name = TRIM(p.category)
+SET p.category_id = c.id;
+
+DROP INDEX idx_products_category ON products;
+
+ALTER TABLE products
+    MODIFY COLUMN category_id INT NOT NULL,
+    ADD CONSTRAINT fk_products_category FOREIGN KEY (category_id) REFERENCES categories(id),
+    DROP COLUMN category;
+
+-- Inventario: stock por producto y libro de movimientos de solo inserción.
+-- Sin FK a products para que el historial sobreviva al borrado del producto.
+ALTER TABLE products
+    ADD COLUMN stock INT NOT NULL DEFAULT 0,
+    ADD COLUMN allow_backorder BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    type ENUM('receipt', 'sale', 'adjustment', 'return') NOT NULL,
+    quantity INT NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INT NULL,
+    stock_after INT NOT NULL,
+    created_at DATETIME NOT NULL,
+    KEY idx_stock_movements_product (product_id, id),
+    CONSTRAINT fk_stock_movements_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE SET NULL
+);
+
+-- Umbral de reposición por producto para las alertas de stock bajo
+ALTER TABLE products ADD COLUMN reorder_threshold INT NOT NULL DEFAULT 0;
+
+-- Autor de cada comentario por ID: la exportación y la anonimización dejan de depender del nombre,
+-- que antes enviaba el cliente. Los comentarios anteriores se asocian por nombre, el único dato que hay.
+ALTER TABLE comments
+    ADD COLUMN user_id INT NULL,
+    ADD KEY idx_comments_user (user_id),
+    ADD CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE SET NULL;
+
+UPDATE comments c
+JOIN user u ON u.userName = c.user_name
+SET c.user_id = u.id
+WHERE c.user_id IS NULL;
@@ -28,26 +28,30 @@ func main() {
 
 	// Inicializar repositorios MySQL
 	productRepo := infraestructure.NewMySQL()
-	userRepo := userInfra.NewMySQL()
+
+	// Dependencias de usuarios (repositorio, tokens y middleware de autenticación)
+	userDeps := userInfra.NewUserDependencies()
 
 	// Configurar rutas de usuarios
 	userGroup := r.Group("/api/v1")
-	userInfra.RegisterRoutes(userGroup, userRepo)
+	userInfra.RegisterRoutes(userGroup, userDeps)
+	// Archivos subidos (avatares) fuera de /api/v1
+	userInfra.RegisterStaticRoutes(r, userDeps)
 
 	// Configurar rutas de productos
 	productGroup := r.Group("/api/v1")
-	infraestructure.RegisterRoutes(productGroup, productRepo)
+	infraestructure.RegisterRoutes(productGroup, productRepo, userDeps.Auth)
 
 	// Configurar rutas de comentarios
-	commentInfra.InitComments(r, dbConfig.DB)
+	commentInfra.InitComments(r, dbConfig.DB, userDeps.Auth)
 
 	// Configurar rutas de categorías
 	categoryGroup := r.Group("/api/v1")
-	categoryInfra.RegisterCategoryRoutes(categoryGroup)
+	categoryInfra.RegisterCategoryRoutes(categoryGroup, userDeps.Auth)
 
 	// Configurar WebSocket
 	go wsocket.WSHub.Run()
-	r.GET("/ws", wsocket.HandleWebSocket)
+	r.GET("/ws", userDeps.WebSocketAuth, wsocket.HandleWebSocket)
 
 	// Configurar servidor
 	r.SetTrustedProxies([]string{"127.0.0.1"})
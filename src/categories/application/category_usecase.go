@@ -3,18 +3,31 @@ package application
 import (
 	"errors"
 	"expresApi/src/categories/domain"
+	"fmt"
 	"strings"
 )
 
+// Políticas al desactivar una categoría que tiene productos
+const (
+	// DeletePolicyRestrict impide desactivarla mientras tenga productos
+	DeletePolicyRestrict = "restrict"
+	// DeletePolicyCascade la desactiva y sus productos dejan de aparecer en el catálogo
+	DeletePolicyCascade = "cascade"
+)
+
+var ErrCategoryInUse = errors.New("la categoría tiene productos asignados")
+
 // CategoryUseCase maneja la lógica de negocio para categorías
 type CategoryUseCase struct {
-	repository domain.ICategoryRepository
+	repository   domain.ICategoryRepository
+	deletePolicy string
 }
 
 // NewCategoryUseCase crea una nueva instancia del caso de uso
-func NewCategoryUseCase(repository domain.ICategoryRepository) *CategoryUseCase {
+func NewCategoryUseCase(repository domain.ICategoryRepository, deletePolicy string) *CategoryUseCase {
 	return &CategoryUseCase{
-		repository: repository,
+		repository:   repository,
+		deletePolicy: deletePolicy,
 	}
 }
 
@@ -80,6 +93,13 @@ func (uc *CategoryUseCase) UpdateCategory(id int, request domain.UpdateCategoryR
 		return nil, errors.New("categoría no encontrada")
 	}
 
+	// Desactivar la categoría desde la edición equivale a eliminarla
+	if request.IsActive != nil && !*request.IsActive && existingCategory.IsActive {
+		if err := uc.checkDeletePolicy(id); err != nil {
+			return nil, err
+		}
+	}
+
 	// Si se está actualizando el nombre, verificar que no exista otra categoría con ese nombre
 	if request.Name != "" && request.Name != existingCategory.Name {
 		duplicateCategory, _ := uc.repository.GetCategoryByName(request.Name)
@@ -108,5 +128,25 @@ func (uc *CategoryUseCase) DeleteCategory(id int) error {
 		return errors.New("categoría no encontrada")
 	}
 
+	if err := uc.checkDeletePolicy(id); err != nil {
+		return err
+	}
+
 	return uc.repository.DeleteCategory(id)
 }
+
+// checkDeletePolicy aplica la política configurada antes de desactivar la categoría
+func (uc *CategoryUseCase) checkDeletePolicy(id int) error {
+	if uc.deletePolicy == DeletePolicyCascade {
+		return nil
+	}
+
+	products, err := uc.repository.CountProducts(id)
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return fmt.Errorf("%w: %d producto(s); reasígnalos antes de eliminarla", ErrCategoryInUse, products)
+	}
+	return nil
+}
@@ -38,4 +38,6 @@ type ICategoryRepository interface {
 	UpdateCategory(id int, category UpdateCategoryRequest) (*Category, error)
 	DeleteCategory(id int) error
 	GetActiveCategories() ([]Category, error)
+	// CountProducts cuenta los productos asignados a la categoría
+	CountProducts(id int) (int, error)
 }
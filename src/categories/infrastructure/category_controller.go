@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"encoding/json"
+	"errors"
 	"expresApi/src/categories/application"
 	"expresApi/src/categories/domain"
 	wsocket "expresApi/src/websocket"
@@ -156,7 +157,7 @@ func (c *CategoryController) UpdateCategory(ctx *gin.Context) {
 
 	category, err := c.useCase.UpdateCategory(id, request)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(categoryErrorStatus(err), gin.H{
 			"error":   "Error al actualizar categoría",
 			"details": err.Error(),
 		})
@@ -200,7 +201,7 @@ func (c *CategoryController) DeleteCategory(ctx *gin.Context) {
 
 	err = c.useCase.DeleteCategory(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		ctx.JSON(categoryErrorStatus(err), gin.H{
 			"error":   "Error al eliminar categoría",
 			"details": err.Error(),
 		})
@@ -225,3 +226,11 @@ func (c *CategoryController) DeleteCategory(ctx *gin.Context) {
 		"message": "Categoría eliminada exitosamente",
 	})
 }
+
+// categoryErrorStatus responde 409 cuando la política de eliminación impide desactivar la categoría
+func categoryErrorStatus(err error) int {
+	if errors.Is(err, application.ErrCategoryInUse) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
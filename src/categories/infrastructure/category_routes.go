@@ -2,16 +2,19 @@ package infrastructure
 
 import (
 	"expresApi/src/categories/application"
+	"expresApi/src/config/middleware"
+	userDomain "expresApi/src/users/domain"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SetupCategoryRoutes configura las rutas para categorías
-func SetupCategoryRoutes(r *gin.RouterGroup) {
+func SetupCategoryRoutes(r *gin.RouterGroup, auth gin.HandlerFunc) {
 	// Crear dependencias
 	repository := NewMySQLCategoryRepository()
-	useCase := application.NewCategoryUseCase(repository)
+	useCase := application.NewCategoryUseCase(repository, NewDeletePolicy())
 	controller := NewCategoryController(useCase)
+	canWrite := middleware.RequirePermission(userDomain.PermissionCategoriesWrite)
 
 	// Configurar rutas
 	categoryGroup := r.Group("/categories")
@@ -26,17 +29,17 @@ func SetupCategoryRoutes(r *gin.RouterGroup) {
 		categoryGroup.GET("/:id", controller.GetCategoryByID)
 
 		// POST /api/v1/categories - Crear nueva categoría
-		categoryGroup.POST("", controller.CreateCategory)
+		categoryGroup.POST("", auth, canWrite, controller.CreateCategory)
 
 		// PUT /api/v1/categories/:id - Actualizar categoría
-		categoryGroup.PUT("/:id", controller.UpdateCategory)
+		categoryGroup.PUT("/:id", auth, canWrite, controller.UpdateCategory)
 
 		// DELETE /api/v1/categories/:id - Eliminar categoría (soft delete)
-		categoryGroup.DELETE("/:id", controller.DeleteCategory)
+		categoryGroup.DELETE("/:id", auth, canWrite, controller.DeleteCategory)
 	}
 }
 
 // RegisterCategoryRoutes registra las rutas en un grupo existente
-func RegisterCategoryRoutes(r *gin.RouterGroup) {
-	SetupCategoryRoutes(r)
+func RegisterCategoryRoutes(r *gin.RouterGroup, auth gin.HandlerFunc) {
+	SetupCategoryRoutes(r, auth)
 }
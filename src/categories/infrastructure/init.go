@@ -2,20 +2,21 @@ package infrastructure
 
 import (
 	"expresApi/src/categories/application"
+	"expresApi/src/config"
 	"log"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitCategories inicializa el módulo de categorías
-func InitCategories(r *gin.Engine) {
+func InitCategories(r *gin.Engine, auth gin.HandlerFunc) {
 	log.Println("Inicializando módulo de categorías...")
 
 	// Crear grupo de rutas API
 	apiGroup := r.Group("/api/v1")
 
 	// Configurar rutas de categorías
-	SetupCategoryRoutes(apiGroup)
+	SetupCategoryRoutes(apiGroup, auth)
 
 	log.Println("Módulo de categorías inicializado correctamente")
 }
@@ -23,6 +24,17 @@ func InitCategories(r *gin.Engine) {
 // GetCategoryDependencies retorna las dependencias para usar en otros módulos
 func GetCategoryDependencies() (*application.CategoryUseCase, error) {
 	repository := NewMySQLCategoryRepository()
-	useCase := application.NewCategoryUseCase(repository)
+	useCase := application.NewCategoryUseCase(repository, NewDeletePolicy())
 	return useCase, nil
 }
+
+// NewDeletePolicy lee CATEGORY_DELETE_POLICY (restrict | cascade); por defecto restrict
+func NewDeletePolicy() string {
+	switch policy := config.GetEnv("CATEGORY_DELETE_POLICY", application.DeletePolicyRestrict); policy {
+	case application.DeletePolicyRestrict, application.DeletePolicyCascade:
+		return policy
+	default:
+		log.Fatalf("Error al configurar las categorías: CATEGORY_DELETE_POLICY=%q no es válido (restrict | cascade)", policy)
+		return ""
+	}
+}
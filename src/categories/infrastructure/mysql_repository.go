@@ -240,6 +240,20 @@ func (r *MySQLCategoryRepository) DeleteCategory(id int) error {
 	return nil
 }
 
+// CountProducts cuenta los productos asignados a la categoría
+func (r *MySQLCategoryRepository) CountProducts(id int) (int, error) {
+	row, err := r.conn.FetchRow(`SELECT COUNT(*) FROM products WHERE category_id = ?`, id)
+	if err != nil {
+		return 0, fmt.Errorf("error al contar los productos de la categoría: %v", err)
+	}
+
+	var total int
+	if err := row.Scan(&total); err != nil {
+		return 0, fmt.Errorf("error al contar los productos de la categoría: %v", err)
+	}
+	return total, nil
+}
+
 // scanCategories convierte las filas de la base de datos en estructuras Category
 func (r *MySQLCategoryRepository) scanCategories(rows *sql.Rows) ([]domain.Category, error) {
 	var categories []domain.Category
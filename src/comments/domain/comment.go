@@ -12,10 +12,12 @@ type Comment struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
-// CreateCommentRequest estructura para crear un comentario
+// CreateCommentRequest estructura para crear un comentario.
+// UserID y UserName los fija el servidor con el usuario autenticado, nunca el cliente.
 type CreateCommentRequest struct {
 	Comment   string `json:"comment" validate:"required,min=5,max=500"`
-	UserName  string `json:"user_name" validate:"required,min=2,max=100"`
+	UserID    int32  `json:"-"`
+	UserName  string `json:"-"`
 	Rating    int    `json:"rating" validate:"required,min=1,max=5"`
 	ProductID int    `json:"product_id" validate:"required,min=1"`
 }
@@ -29,6 +31,8 @@ type CommentRepository interface {
 	DeleteByProductID(productID int) error // Nuevo método
 	GetAll() ([]Comment, error)
 	GetStats(productID int) (*CommentStats, error)
+	GetByUserID(userID int32) ([]Comment, error)
+	ReplaceUserName(userID int32, replacement string) (int64, error)
 }
 
 // CommentStats estadísticas de comentarios por producto
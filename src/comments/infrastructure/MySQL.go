@@ -22,11 +22,11 @@ func NewMySQLCommentRepository(db *sql.DB) domain.CommentRepository {
 // Create crea un nuevo comentario
 func (r *MySQLCommentRepository) Create(req domain.CreateCommentRequest) (*domain.Comment, error) {
 	query := `
-		INSERT INTO comments (comment, user_name, rating, product_id, created_at) 
-		VALUES (?, ?, ?, ?, NOW())
+		INSERT INTO comments (comment, user_id, user_name, rating, product_id, created_at) 
+		VALUES (?, ?, ?, ?, ?, NOW())
 	`
 
-	result, err := r.db.Exec(query, req.Comment, req.UserName, req.Rating, req.ProductID)
+	result, err := r.db.Exec(query, req.Comment, req.UserID, req.UserName, req.Rating, req.ProductID)
 	if err != nil {
 		return nil, fmt.Errorf("error creating comment: %w", err)
 	}
@@ -289,3 +289,79 @@ func (r *MySQLCommentRepository) GetStats(productID int) (*domain.CommentStats,
 
 	return &stats, nil
 }
+
+// GetByUserID obtiene los comentarios publicados por un usuario
+func (r *MySQLCommentRepository) GetByUserID(userID int32) ([]domain.Comment, error) {
+	query := `
+		SELECT id, comment, user_name, rating, product_id, 
+		       DATE_FORMAT(created_at, '%Y-%m-%d %H:%i:%s') as created_at_formatted
+		FROM comments 
+		WHERE user_id = ? 
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying comments by user: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []domain.Comment{}
+	for rows.Next() {
+		var comment domain.Comment
+		var createdAtStr string
+
+		err := rows.Scan(
+			&comment.ID,
+			&comment.Comment,
+			&comment.UserName,
+			&comment.Rating,
+			&comment.ProductID,
+			&createdAtStr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning comment: %w", err)
+		}
+
+		if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+			comment.CreatedAt = createdAt
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+// ReplaceUserName cambia el autor de los comentarios de un usuario; el rating se conserva
+// para que las estadísticas del producto no cambien
+func (r *MySQLCommentRepository) ReplaceUserName(userID int32, replacement string) (int64, error) {
+	return replaceUserName(r.db, userID, replacement)
+}
+
+// ReplaceUserNameTx hace lo mismo que ReplaceUserName dentro de una transacción abierta por
+// otro módulo, para que el cambio se confirme o se deshaga junto con el resto de la operación
+func ReplaceUserNameTx(tx *sql.Tx, userID int32, replacement string) (int64, error) {
+	return replaceUserName(tx, userID, replacement)
+}
+
+// sqlExecutor lo cumplen tanto *sql.DB como *sql.Tx
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func replaceUserName(db sqlExecutor, userID int32, replacement string) (int64, error) {
+	query := `UPDATE comments SET user_name = ? WHERE user_id = ?`
+
+	result, err := db.Exec(query, replacement, userID)
+	if err != nil {
+		return 0, fmt.Errorf("error replacing comment author: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
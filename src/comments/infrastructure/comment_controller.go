@@ -8,6 +8,7 @@ import (
 
 	"expresApi/src/comments/application"
 	"expresApi/src/comments/domain"
+	"expresApi/src/config/middleware"
 	wsocket "expresApi/src/websocket"
 
 	"github.com/gin-gonic/gin"
@@ -37,6 +38,15 @@ func (c *CommentController) CreateComment(ctx *gin.Context) {
 		return
 	}
 
+	// El autor es siempre el usuario autenticado; así sus comentarios se exportan y anonimizan con él
+	user, ok := middleware.CurrentUser(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso requerido"})
+		return
+	}
+	request.UserID = user.ID
+	request.UserName = user.UserName
+
 	comment, err := c.useCase.CreateComment(request)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
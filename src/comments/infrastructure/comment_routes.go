@@ -1,15 +1,21 @@
 package infrastructure
 
 import (
+	"expresApi/src/config/middleware"
+	userDomain "expresApi/src/users/domain"
+
 	"github.com/gin-gonic/gin"
 )
 
 // SetupCommentRoutes configura las rutas para comentarios
-func SetupCommentRoutes(router *gin.Engine, controller *CommentController) {
+func SetupCommentRoutes(router *gin.Engine, controller *CommentController, auth gin.HandlerFunc) {
+	canWrite := middleware.RequirePermission(userDomain.PermissionCommentsWrite)
+	canModerate := middleware.RequirePermission(userDomain.PermissionCommentsModerate)
+
 	commentGroup := router.Group("/api/comments")
 	{
 		// Crear comentario
-		commentGroup.POST("/", controller.CreateComment)
+		commentGroup.POST("/", auth, canWrite, controller.CreateComment)
 
 		// Obtener todos los comentarios
 		commentGroup.GET("/", controller.GetAllComments)
@@ -24,6 +30,6 @@ func SetupCommentRoutes(router *gin.Engine, controller *CommentController) {
 		commentGroup.GET("/product/:productId/stats", controller.GetCommentStats)
 
 		// Eliminar comentario
-		commentGroup.DELETE("/:id", controller.DeleteComment)
+		commentGroup.DELETE("/:id", auth, canModerate, controller.DeleteComment)
 	}
 }
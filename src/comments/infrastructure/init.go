@@ -7,10 +7,10 @@ import (
 )
 
 // InitComments inicializa el módulo de comentarios
-func InitComments(router *gin.Engine, db *sql.DB) {
+func InitComments(router *gin.Engine, db *sql.DB, auth gin.HandlerFunc) {
 	// Crear dependencias
 	dependencies := NewCommentDependencies(db)
 
 	// Configurar rutas
-	SetupCommentRoutes(router, dependencies.Controller)
+	SetupCommentRoutes(router, dependencies.Controller, auth)
 }
@@ -61,7 +61,7 @@ func (conn *Conn_MySQL) ExecutePreparedQuery(query string, values ...interface{}
 
 	result, err := stmt.Exec(values...)
 	if err != nil {
-		return nil, fmt.Errorf("error al ejecutar la consulta preparada: %v", err)
+		return nil, fmt.Errorf("error al ejecutar la consulta preparada: %w", err)
 	}
 
 	return result, nil
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+var loadEnvOnce sync.Once
+
+// loadEnv carga el archivo .env una sola vez; las variables ya definidas no se sobrescriben
+func loadEnv() {
+	loadEnvOnce.Do(func() {
+		_ = godotenv.Load(".env")
+	})
+}
+
+// GetEnv obtiene una variable de entorno o el valor por defecto si no está definida
+func GetEnv(key string, defaultValue string) string {
+	loadEnv()
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// GetEnvInt obtiene una variable de entorno numérica o el valor por defecto
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(GetEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvBool obtiene una variable de entorno booleana o el valor por defecto
+func GetEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(GetEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvDuration obtiene una duración (ej. "15m", "24h") o el valor por defecto
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(GetEnv(key, ""))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	userDomain "expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Claves donde el middleware de autenticación guarda el usuario y sus permisos
+const (
+	ContextUserKey         = "user"
+	ContextPermissionsKey  = "permissions"
+	ContextAuthMethodKey   = "auth_method"
+	ContextSessionIDKey    = "session_id"
+	ContextImpersonatorKey = "impersonator_id"
+)
+
+// Formas de autenticación aceptadas por el middleware
+const (
+	AuthMethodSession = "session"
+	AuthMethodApiKey  = "api_key"
+	// Token de suplantación emitido a un administrador para actuar como otro usuario
+	AuthMethodImpersonation = "impersonation"
+)
+
+// SetCurrentUser guarda el usuario autenticado en el contexto de la petición
+func SetCurrentUser(c *gin.Context, user *userDomain.User) {
+	c.Set(ContextUserKey, user)
+}
+
+// CurrentUser obtiene el usuario autenticado del contexto, si existe
+func CurrentUser(c *gin.Context) (*userDomain.User, bool) {
+	value, exists := c.Get(ContextUserKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*userDomain.User)
+	return user, ok
+}
+
+// SetAuthMethod guarda cómo se autenticó la petición (sesión o API key)
+func SetAuthMethod(c *gin.Context, method string) {
+	c.Set(ContextAuthMethodKey, method)
+}
+
+// AuthMethod devuelve cómo se autenticó la petición
+func AuthMethod(c *gin.Context) string {
+	return c.GetString(ContextAuthMethodKey)
+}
+
+// SetSessionID guarda la sesión de servidor a la que pertenece el access token
+func SetSessionID(c *gin.Context, sessionID string) {
+	c.Set(ContextSessionIDKey, sessionID)
+}
+
+// CurrentSessionID devuelve la sesión actual; vacío si la petición usa una API key
+func CurrentSessionID(c *gin.Context) string {
+	return c.GetString(ContextSessionIDKey)
+}
+
+// SetImpersonator guarda el administrador que actúa como el usuario autenticado
+func SetImpersonator(c *gin.Context, impersonatorID int32) {
+	c.Set(ContextImpersonatorKey, impersonatorID)
+}
+
+// ImpersonatorID devuelve el administrador que suplanta al usuario, si la petición es una suplantación
+func ImpersonatorID(c *gin.Context) (int32, bool) {
+	value, exists := c.Get(ContextImpersonatorKey)
+	if !exists {
+		return 0, false
+	}
+	id, ok := value.(int32)
+	return id, ok
+}
+
+// RequireSession rechaza las peticiones autenticadas con API key o con un token de suplantación.
+// Se usa en las rutas que gestionan credenciales.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if AuthMethod(c) != AuthMethodSession {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Esta operación requiere iniciar sesión"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// SetPermissions guarda los permisos efectivos del usuario autenticado
+func SetPermissions(c *gin.Context, permissions []string) {
+	set := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		set[permission] = true
+	}
+	c.Set(ContextPermissionsKey, set)
+}
+
+// HasPermission indica si el usuario autenticado tiene el permiso indicado
+func HasPermission(c *gin.Context, permission string) bool {
+	value, exists := c.Get(ContextPermissionsKey)
+	if !exists {
+		return false
+	}
+	set, ok := value.(map[string]bool)
+	return ok && set[permission]
+}
+
+// CurrentPermissions devuelve los permisos efectivos del usuario autenticado; vacío si es anónimo
+func CurrentPermissions(c *gin.Context) []string {
+	value, exists := c.Get(ContextPermissionsKey)
+	if !exists {
+		return nil
+	}
+	set, _ := value.(map[string]bool)
+	permissions := make([]string, 0, len(set))
+	for permission := range set {
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+// RequirePermission rechaza la petición si el usuario autenticado no tiene el permiso.
+// Debe registrarse después del middleware de autenticación.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentUser(c); !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso requerido"})
+			return
+		}
+		if !HasPermission(c, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permiso insuficiente", "permission": permission})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission permite la petición si el parámetro de ruta idParam
+// corresponde al usuario autenticado o si tiene el permiso indicado.
+// Una API key no cuenta como el propio usuario: solo pasa si sus scopes incluyen el permiso.
+func RequireSelfOrPermission(idParam string, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso requerido"})
+			return
+		}
+		id, err := strconv.Atoi(c.Param(idParam))
+		if err == nil && int32(id) == user.ID && AuthMethod(c) != AuthMethodApiKey {
+			c.Next()
+			return
+		}
+		if !HasPermission(c, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permiso insuficiente", "permission": permission})
+			return
+		}
+		c.Next()
+	}
+}
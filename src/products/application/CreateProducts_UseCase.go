@@ -5,19 +5,24 @@ import (
 )
 
 type CreateProduct struct {
-	db domain.IProduct
+	db         domain.IProduct
+	categories domain.IProductCategories
 }
 
-func NewCreateProduct(db domain.IProduct) *CreateProduct {
-	return &CreateProduct{db: db}
+func NewCreateProduct(db domain.IProduct, categories domain.IProductCategories) *CreateProduct {
+	return &CreateProduct{db: db, categories: categories}
 }
 
-func (ct *CreateProduct) Execute(name string, description string, price float64, category string, imageURL string) error {
+func (ct *CreateProduct) Execute(name string, description string, price float64, categoryID int, imageURL string) error {
+	if err := validateCategory(ct.categories, categoryID); err != nil {
+		return err
+	}
+
 	// Crear el objeto producto
-	product := domain.NewProduct(name, description, price, category, imageURL)
+	product := domain.NewProduct(name, description, price, categoryID, imageURL)
 
 	// Guardar el producto una sola vez
-	err := ct.db.SaveProduct(product.Name, product.Description, product.Price, product.Category, product.ImageURL)
+	err := ct.db.SaveProduct(product.Name, product.Description, product.Price, product.CategoryID, product.ImageURL)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"expresApi/src/products/domain"
+)
+
+type GetProduct struct {
+	db      domain.IProduct
+	ratings domain.IProductRatings
+}
+
+func NewGetProduct(db domain.IProduct, ratings domain.IProductRatings) *GetProduct {
+	return &GetProduct{db: db, ratings: ratings}
+}
+
+// Execute devuelve el producto con su categoría y el resumen de calificaciones
+func (gp *GetProduct) Execute(id int32) (*domain.ProductDetail, error) {
+	product, err := gp.db.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.ProductDetail{
+		Product: *product,
+		Rating:  domain.RatingSummary{Distribution: emptyDistribution()},
+	}
+
+	// Sin repositorio de comentarios el detalle sale con las calificaciones en cero
+	if gp.ratings == nil {
+		return detail, nil
+	}
+	summary, err := gp.ratings.GetRatingSummary(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	detail.Rating.AverageRating = summary.AverageRating
+	detail.Rating.TotalComments = summary.TotalComments
+	for rating, count := range summary.Distribution {
+		detail.Rating.Distribution[rating] = count
+	}
+
+	return detail, nil
+}
+
+// emptyDistribution inicia en cero las calificaciones de 1 a 5 para que el cliente no tenga que rellenarlas
+func emptyDistribution() map[int]int {
+	distribution := make(map[int]int, 5)
+	for rating := 1; rating <= 5; rating++ {
+		distribution[rating] = 0
+	}
+	return distribution
+}
@@ -0,0 +1,24 @@
+package application
+
+import (
+	"expresApi/src/products/domain"
+)
+
+// validateCategory comprueba que la categoría exista y esté activa antes de asignarla a un producto
+func validateCategory(categories domain.IProductCategories, categoryID int) error {
+	if categoryID <= 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	category, err := categories.GetByID(categoryID)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return domain.ErrCategoryNotFound
+	}
+	if !category.IsActive {
+		return domain.ErrCategoryInactive
+	}
+	return nil
+}
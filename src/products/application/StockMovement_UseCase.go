@@ -0,0 +1,205 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/products/domain"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultMovementPageSize = 50
+	MaxMovementPageSize     = 200
+	maxMovementReason       = 255
+)
+
+var (
+	ErrInvalidMovementType     = errors.New("tipo de movimiento inválido (receipt, sale, adjustment, return)")
+	ErrInvalidMovementQuantity = errors.New("la cantidad debe ser positiva; en los ajustes puede ser negativa pero no cero")
+	ErrMovementReasonRequired  = errors.New("los ajustes requieren un motivo")
+	ErrMovementReasonTooLong   = errors.New("el motivo no puede superar los 255 caracteres")
+	ErrInvalidReorderThreshold = errors.New("el umbral de reposición no puede ser negativo")
+)
+
+// Signo con el que cada tipo de movimiento afecta al stock; los ajustes usan el signo de la cantidad
+var movementSigns = map[string]int{
+	domain.MovementReceipt:    1,
+	domain.MovementSale:       -1,
+	domain.MovementAdjustment: 1,
+	domain.MovementReturn:     1,
+}
+
+type RecordStockMovement struct {
+	db       domain.IProduct
+	ledger   domain.IStockLedger
+	notifier domain.IStockAlertNotifier
+}
+
+func NewRecordStockMovement(db domain.IProduct, ledger domain.IStockLedger, notifier domain.IStockAlertNotifier) *RecordStockMovement {
+	return &RecordStockMovement{db: db, ledger: ledger, notifier: notifier}
+}
+
+// Execute registra el movimiento. quantity es siempre positiva salvo en los ajustes,
+// donde el signo indica si el stock sube o baja.
+func (rs *RecordStockMovement) Execute(productID int32, movementType string, quantity int, reason string, userID int32) (*domain.StockMovement, error) {
+	sign, ok := movementSigns[movementType]
+	if !ok {
+		return nil, ErrInvalidMovementType
+	}
+	if quantity == 0 || quantity < 0 && movementType != domain.MovementAdjustment {
+		return nil, ErrInvalidMovementQuantity
+	}
+
+	reason = strings.TrimSpace(reason)
+	if movementType == domain.MovementAdjustment && reason == "" {
+		return nil, ErrMovementReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > maxMovementReason {
+		return nil, ErrMovementReasonTooLong
+	}
+
+	movement := &domain.StockMovement{
+		ProductID: productID,
+		Type:      movementType,
+		Quantity:  sign * quantity,
+		Reason:    reason,
+		UserID:    userID,
+	}
+	if err := rs.ledger.Record(movement); err != nil {
+		return nil, err
+	}
+	if movement.Alert != "" {
+		rs.notify(movement)
+	}
+	return movement, nil
+}
+
+// notify avisa del cruce de umbral; el movimiento ya está confirmado, así que un fallo aquí solo se registra
+func (rs *RecordStockMovement) notify(movement *domain.StockMovement) {
+	alert := domain.StockAlert{
+		Type:       movement.Alert,
+		ProductID:  movement.ProductID,
+		Stock:      movement.StockAfter,
+		MovementID: movement.ID,
+		CreatedAt:  movement.CreatedAt,
+	}
+	if product, err := rs.db.GetByID(movement.ProductID); err == nil {
+		alert.ProductName = product.Name
+		alert.ReorderThreshold = product.ReorderThreshold
+	} else {
+		log.Printf("[Stock] - No se pudo leer el producto ID: %d para la alerta: %v", movement.ProductID, err)
+	}
+	rs.notifier.Notify(alert)
+}
+
+// StockMovementPage es una página del historial de inventario de un producto
+type StockMovementPage struct {
+	Movements []domain.StockMovement
+	Total     int
+	Limit     int
+	Offset    int
+}
+
+type ListStockMovements struct {
+	db     domain.IProduct
+	ledger domain.IStockLedger
+}
+
+func NewListStockMovements(db domain.IProduct, ledger domain.IStockLedger) *ListStockMovements {
+	return &ListStockMovements{db: db, ledger: ledger}
+}
+
+// Execute devuelve los movimientos del producto, del más reciente al más antiguo
+func (ls *ListStockMovements) Execute(productID int32, limit int, offset int) (*StockMovementPage, error) {
+	if limit < 0 || offset < 0 {
+		return nil, ErrInvalidProductPage
+	}
+	if limit == 0 {
+		limit = DefaultMovementPageSize
+	}
+	if limit > MaxMovementPageSize {
+		limit = MaxMovementPageSize
+	}
+
+	// El historial de un producto que no existe es un 404, no una lista vacía
+	if _, err := ls.db.GetByID(productID); err != nil {
+		return nil, err
+	}
+
+	total, err := ls.ledger.CountByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	movements, err := ls.ledger.ListByProduct(productID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &StockMovementPage{Movements: movements, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+type SetBackorders struct {
+	db domain.IProduct
+}
+
+func NewSetBackorders(db domain.IProduct) *SetBackorders {
+	return &SetBackorders{db: db}
+}
+
+// Execute activa o desactiva los pedidos pendientes (stock negativo) del producto
+func (sb *SetBackorders) Execute(productID int32, allow bool) (*domain.Product, error) {
+	if err := sb.db.SetAllowBackorder(productID, allow); err != nil {
+		return nil, err
+	}
+	return sb.db.GetByID(productID)
+}
+
+type SetReorderThreshold struct {
+	db domain.IProduct
+}
+
+func NewSetReorderThreshold(db domain.IProduct) *SetReorderThreshold {
+	return &SetReorderThreshold{db: db}
+}
+
+// Execute cambia el umbral de reposición; no dispara alertas, solo los movimientos lo hacen
+func (st *SetReorderThreshold) Execute(productID int32, threshold int) (*domain.Product, error) {
+	if threshold < 0 {
+		return nil, ErrInvalidReorderThreshold
+	}
+	if err := st.db.SetReorderThreshold(productID, threshold); err != nil {
+		return nil, err
+	}
+	return st.db.GetByID(productID)
+}
+
+// LowStockProduct es un producto con stock bajo o agotado y su estado
+type LowStockProduct struct {
+	domain.Product
+	Status string `json:"status"`
+}
+
+type ListLowStock struct {
+	db domain.IProduct
+}
+
+func NewListLowStock(db domain.IProduct) *ListLowStock {
+	return &ListLowStock{db: db}
+}
+
+// Execute devuelve los productos en o por debajo de su umbral de reposición, y los agotados
+func (ll *ListLowStock) Execute() ([]LowStockProduct, error) {
+	products, err := ll.db.FindBelowThreshold()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LowStockProduct, 0, len(products))
+	for _, product := range products {
+		result = append(result, LowStockProduct{
+			Product: product,
+			Status:  domain.StockStatus(product.Stock, product.ReorderThreshold),
+		})
+	}
+	return result, nil
+}
@@ -5,13 +5,17 @@ import (
 )
 
 type UpdateProduct struct {
-	repo domain.IProduct
+	repo       domain.IProduct
+	categories domain.IProductCategories
 }
 
-func NewUpdateProduct(repo domain.IProduct) *UpdateProduct {
-	return &UpdateProduct{repo: repo}
+func NewUpdateProduct(repo domain.IProduct, categories domain.IProductCategories) *UpdateProduct {
+	return &UpdateProduct{repo: repo, categories: categories}
 }
 
-func (u *UpdateProduct) Execute(id string, name string, description string, price float64, category string, imageURL string) error {
-	return u.repo.Update(id, name, description, price, category, imageURL)
+func (u *UpdateProduct) Execute(id string, name string, description string, price float64, categoryID int, imageURL string) error {
+	if err := validateCategory(u.categories, categoryID); err != nil {
+		return err
+	}
+	return u.repo.Update(id, name, description, price, categoryID, imageURL)
 }
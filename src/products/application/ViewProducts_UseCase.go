@@ -1,9 +1,50 @@
 package application
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"expresApi/src/products/domain"
 )
 
+const (
+	DefaultProductPageSize = 20
+	MaxProductPageSize     = 100
+)
+
+var (
+	ErrInvalidProductSort   = errors.New("campo de orden no permitido")
+	ErrInvalidProductCursor = errors.New("cursor inválido")
+	ErrInvalidProductPage   = errors.New("limit y offset deben ser positivos")
+	ErrInvalidProductFilter = errors.New("rango de precio o calificación inválido")
+)
+
+// Orden por defecto de cada campo: lo más nuevo y lo mejor calificado primero
+var productSortFields = map[string]bool{
+	domain.ProductSortNewest: true,
+	domain.ProductSortPrice:  false,
+	domain.ProductSortName:   false,
+	domain.ProductSortRating: true,
+}
+
+// ProductPage es una página del listado de productos con el total de resultados
+type ProductPage struct {
+	Products   []domain.Product
+	Total      int
+	Limit      int
+	Offset     int
+	NextCursor string
+}
+
+// productCursorPayload es el contenido codificado en el cursor; incluye el orden para rechazar
+// cursores generados con otro criterio
+type productCursorPayload struct {
+	SortBy   string `json:"s"`
+	SortDesc bool   `json:"d"`
+	Value    string `json:"v"`
+	ID       int32  `json:"id"`
+}
+
 type ViewProduct struct {
 	db domain.IProduct
 }
@@ -12,6 +53,96 @@ func NewViewProduct(db domain.IProduct) *ViewProduct {
 	return &ViewProduct{db: db}
 }
 
-func (vt ViewProduct) Execute() ([]domain.Product, error) {
-	return vt.db.GetAll()
-}
\ No newline at end of file
+// DefaultSortDesc indica si el campo se ordena de forma descendente cuando no se pide un orden
+func DefaultSortDesc(sortBy string) bool {
+	if sortBy == "" {
+		sortBy = domain.ProductSortNewest
+	}
+	return productSortFields[sortBy]
+}
+
+// Execute devuelve una página de productos; cursor es el next_cursor de una página anterior
+func (vt ViewProduct) Execute(query domain.ProductQuery, cursor string) (*ProductPage, error) {
+	if query.SortBy == "" {
+		query.SortBy = domain.ProductSortNewest
+	}
+	if _, ok := productSortFields[query.SortBy]; !ok {
+		return nil, ErrInvalidProductSort
+	}
+	if query.Limit < 0 || query.Offset < 0 {
+		return nil, ErrInvalidProductPage
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultProductPageSize
+	}
+	if query.Limit > MaxProductPageSize {
+		query.Limit = MaxProductPageSize
+	}
+	if !validProductFilters(query) {
+		return nil, ErrInvalidProductFilter
+	}
+
+	if cursor != "" {
+		after, err := decodeProductCursor(cursor, query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+		query.Offset = 0
+	}
+
+	total, err := vt.db.CountProducts(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se pide un registro extra para saber si hay una página siguiente
+	pageQuery := query
+	pageQuery.Limit = query.Limit + 1
+	products, err := vt.db.FindProducts(pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ProductPage{Products: products, Total: total, Limit: query.Limit, Offset: query.Offset}
+	if len(products) > query.Limit {
+		page.Products = products[:query.Limit]
+		last := page.Products[len(page.Products)-1]
+		page.NextCursor = encodeProductCursor(query, &last)
+	}
+	if page.Products == nil {
+		page.Products = []domain.Product{}
+	}
+	return page, nil
+}
+
+func validProductFilters(query domain.ProductQuery) bool {
+	if query.MinPrice != nil && *query.MinPrice < 0 || query.MaxPrice != nil && *query.MaxPrice < 0 {
+		return false
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return false
+	}
+	return query.MinRating == nil || *query.MinRating >= 0 && *query.MinRating <= 5
+}
+
+func encodeProductCursor(query domain.ProductQuery, last *domain.Product) string {
+	payload, _ := json.Marshal(productCursorPayload{SortBy: query.SortBy, SortDesc: query.SortDesc, Value: last.SortValue(query.SortBy), ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeProductCursor(cursor string, query domain.ProductQuery) (*domain.ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidProductCursor
+	}
+
+	var payload productCursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidProductCursor
+	}
+	if payload.SortBy != query.SortBy || payload.SortDesc != query.SortDesc {
+		return nil, ErrInvalidProductCursor
+	}
+	return &domain.ProductCursor{Value: payload.Value, ID: payload.ID}, nil
+}
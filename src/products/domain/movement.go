@@ -1,10 +1,24 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type IProduct interface {
-	SaveProduct(name string, description string, price float64, category string, imageURL string) error
-	GetAll() ([]Product, error)
+	SaveProduct(name string, description string, price float64, categoryID int, imageURL string) error
+	FindProducts(query ProductQuery) ([]Product, error)
+	CountProducts(query ProductQuery) (int, error)
+	// GetByID devuelve ErrProductNotFound si el producto no existe
+	GetByID(id int32) (*Product, error)
 	Delete(id string) error
-	Update(id string, name string, description string, price float64, category string, imageURL string) error
+	Update(id string, name string, description string, price float64, categoryID int, imageURL string) error
+	// SetAllowBackorder devuelve ErrProductNotFound si el producto no existe
+	SetAllowBackorder(id int32, allow bool) error
+	// SetReorderThreshold devuelve ErrProductNotFound si el producto no existe
+	SetReorderThreshold(id int32, threshold int) error
+	// FindBelowThreshold devuelve los productos con stock bajo o agotado, empezando por el menor stock
+	FindBelowThreshold() ([]Product, error)
 }
 
 type Product struct {
@@ -12,16 +26,26 @@ type Product struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
+	CategoryID  int     `json:"category_id"`
 	ImageURL    string  `json:"image_url"`
+	// Category se rellena al leer el producto; no se usa al guardarlo
+	Category *ProductCategory `json:"category"`
+	// Promedio y número de calificaciones de los comentarios; solo lectura
+	AverageRating float64 `json:"average_rating"`
+	RatingCount   int     `json:"rating_count"`
+	// Stock solo cambia a través de los movimientos de inventario
+	Stock          int  `json:"stock"`
+	AllowBackorder bool `json:"allow_backorder"`
+	// ReorderThreshold es el stock a partir del cual se avisa que hay que reponer; 0 = solo avisar al agotarse
+	ReorderThreshold int `json:"reorder_threshold"`
 }
 
-func NewProduct(name string, description string, price float64, category string, imageURL string) *Product {
+func NewProduct(name string, description string, price float64, categoryID int, imageURL string) *Product {
 	return &Product{
 		Name:        name,
 		Description: description,
 		Price:       price,
-		Category:    category,
+		CategoryID:  categoryID,
 		ImageURL:    imageURL,
 	}
 }
@@ -29,3 +53,91 @@ func NewProduct(name string, description string, price float64, category string,
 func (t *Product) SetPrice(price float64) {
 	t.Price = price
 }
+
+// Tipos de movimiento de inventario
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementAdjustment = "adjustment"
+	MovementReturn     = "return"
+)
+
+var ErrInsufficientStock = errors.New("no hay stock suficiente y el producto no admite pedidos pendientes")
+
+// StockMovement es una entrada del libro de inventario; nunca se modifica ni se borra.
+// Quantity lleva signo: positivo suma stock y negativo lo resta.
+type StockMovement struct {
+	ID         int64     `json:"id"`
+	ProductID  int32     `json:"product_id"`
+	Type       string    `json:"type"`
+	Quantity   int       `json:"quantity"`
+	Reason     string    `json:"reason"`
+	UserID     int32     `json:"user_id"`
+	StockAfter int       `json:"stock_after"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Alert es el evento de stock que disparó este movimiento, si cruzó un umbral; no se guarda
+	Alert string `json:"alert,omitempty"`
+}
+
+// ApplyMovement calcula el stock resultante; una salida no puede dejarlo en negativo
+// salvo que el producto admita pedidos pendientes
+func ApplyMovement(stock int, quantity int, allowBackorder bool) (int, error) {
+	result := stock + quantity
+	if quantity < 0 && result < 0 && !allowBackorder {
+		return stock, ErrInsufficientStock
+	}
+	return result, nil
+}
+
+// IStockLedger guarda los movimientos de inventario y mantiene el stock de cada producto
+type IStockLedger interface {
+	// Record aplica el movimiento al stock y lo registra en una sola transacción que bloquea
+	// la fila del producto; completa ID, StockAfter y CreatedAt
+	Record(movement *StockMovement) error
+	ListByProduct(productID int32, limit int, offset int) ([]StockMovement, error)
+	CountByProduct(productID int32) (int, error)
+}
+
+// Eventos de alerta de stock
+const (
+	AlertLowStock   = "product_low_stock"
+	AlertOutOfStock = "product_out_of_stock"
+)
+
+// StockStatus clasifica el stock: agotado (<= 0), bajo (<= umbral) o vacío si está en orden
+func StockStatus(stock int, threshold int) string {
+	switch {
+	case stock <= 0:
+		return AlertOutOfStock
+	case stock <= threshold:
+		return AlertLowStock
+	default:
+		return ""
+	}
+}
+
+// DetectStockAlert devuelve el evento solo cuando el movimiento empeora el estado del stock,
+// de modo que cada cruce de umbral avisa una vez y los movimientos siguientes no repiten la alerta
+func DetectStockAlert(before int, after int, threshold int) string {
+	previous, current := StockStatus(before, threshold), StockStatus(after, threshold)
+	if current == previous || current == "" || previous == AlertOutOfStock {
+		return ""
+	}
+	return current
+}
+
+// StockAlert es la notificación de que un producto quedó con stock bajo o agotado
+type StockAlert struct {
+	Type             string    `json:"type"`
+	ProductID        int32     `json:"product_id"`
+	ProductName      string    `json:"product_name"`
+	Stock            int       `json:"stock"`
+	ReorderThreshold int       `json:"reorder_threshold"`
+	MovementID       int64     `json:"movement_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// IStockAlertNotifier entrega las alertas de stock; no debe bloquear el registro del movimiento
+type IStockAlertNotifier interface {
+	Notify(alert StockAlert)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyMovement(t *testing.T) {
+	tests := []struct {
+		name           string
+		stock          int
+		quantity       int
+		allowBackorder bool
+		want           int
+		wantErr        error
+	}{
+		{"entrada", 5, 10, false, 15, nil},
+		{"salida con stock suficiente", 5, -3, false, 2, nil},
+		{"salida que agota el stock", 5, -5, false, 0, nil},
+		{"salida sin stock suficiente", 5, -6, false, 5, ErrInsufficientStock},
+		{"salida sin stock suficiente con pedidos pendientes", 5, -6, true, -1, nil},
+		{"ajuste negativo sin pedidos pendientes", 2, -4, false, 2, ErrInsufficientStock},
+		{"ajuste negativo con pedidos pendientes", 2, -4, true, -2, nil},
+		{"salida desde stock negativo con pedidos pendientes", -2, -1, true, -3, nil},
+		{"salida desde stock negativo sin pedidos pendientes", -2, -1, false, -2, ErrInsufficientStock},
+		// Una entrada siempre se acepta aunque el stock siga en negativo
+		{"entrada que no cubre los pedidos pendientes", -5, 2, false, -3, nil},
+		{"movimiento sin cantidad", 3, 0, false, 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyMovement(tt.stock, tt.quantity, tt.allowBackorder)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, se esperaba %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("stock = %d, se esperaba %d", got, tt.want)
+			}
+		})
+	}
+}
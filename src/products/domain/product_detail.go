@@ -0,0 +1,43 @@
+package domain
+
+import "errors"
+
+var (
+	ErrProductNotFound  = errors.New("producto no encontrado")
+	ErrCategoryNotFound = errors.New("la categoría no existe")
+	ErrCategoryInactive = errors.New("la categoría está desactivada")
+)
+
+// ProductCategory es la categoría embebida en las respuestas de productos
+type ProductCategory struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	IsActive    bool   `json:"is_active"`
+}
+
+// RatingSummary resume las calificaciones de los comentarios del producto
+type RatingSummary struct {
+	AverageRating float64 `json:"average_rating"`
+	TotalComments int     `json:"total_comments"`
+	// Distribution incluye siempre las calificaciones de 1 a 5, aunque no tengan comentarios
+	Distribution map[int]int `json:"distribution"`
+}
+
+// ProductDetail es el producto con el resumen de calificaciones
+type ProductDetail struct {
+	Product
+	Rating RatingSummary `json:"rating"`
+}
+
+// IProductRatings obtiene el resumen de calificaciones desde el módulo de comentarios
+type IProductRatings interface {
+	GetRatingSummary(productID int32) (*RatingSummary, error)
+}
+
+// IProductCategories consulta el módulo de categorías para validar la categoría de un producto
+type IProductCategories interface {
+	// GetByID devuelve nil si la categoría no existe
+	GetByID(id int) (*ProductCategory, error)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "strconv"
+
+// Campos por los que se permite ordenar el listado de productos
+const (
+	ProductSortNewest = "newest"
+	ProductSortPrice  = "price"
+	ProductSortName   = "name"
+	ProductSortRating = "rating"
+)
+
+// ProductQuery describe los filtros, el orden y la página de un listado de productos.
+// Si After está presente se usa paginación por cursor y Offset se ignora.
+type ProductQuery struct {
+	CategoryID int
+	// Category filtra por el nombre de la categoría
+	Category  string
+	Search    string
+	MinPrice  *float64
+	MaxPrice  *float64
+	MinRating *float64
+	SortBy    string
+	SortDesc  bool
+	Limit     int
+	Offset    int
+	After     *ProductCursor
+}
+
+// ProductCursor es la posición del último producto devuelto: el valor del campo de orden y su ID
+type ProductCursor struct {
+	Value string
+	ID    int32
+}
+
+// SortValue devuelve el valor del campo de orden del producto, usado para construir el cursor
+func (t *Product) SortValue(sortBy string) string {
+	switch sortBy {
+	case ProductSortPrice:
+		return strconv.FormatFloat(t.Price, 'f', -1, 64)
+	case ProductSortName:
+		return t.Name
+	case ProductSortRating:
+		return strconv.FormatFloat(t.AverageRating, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import "testing"
+
+func TestStockStatus(t *testing.T) {
+	tests := []struct {
+		stock     int
+		threshold int
+		want      string
+	}{
+		{10, 5, ""},
+		{6, 5, ""},
+		{5, 5, AlertLowStock},
+		{1, 5, AlertLowStock},
+		{0, 5, AlertOutOfStock},
+		{-3, 5, AlertOutOfStock},
+		// Sin umbral solo se avisa al agotarse
+		{1, 0, ""},
+		{0, 0, AlertOutOfStock},
+	}
+
+	for _, tt := range tests {
+		if got := StockStatus(tt.stock, tt.threshold); got != tt.want {
+			t.Errorf("StockStatus(%d, %d) = %q, se esperaba %q", tt.stock, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestDetectStockAlert(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    int
+		after     int
+		threshold int
+		want      string
+	}{
+		{"normal a normal", 20, 15, 5, ""},
+		{"normal a bajo", 10, 5, 5, AlertLowStock},
+		{"normal a agotado", 10, 0, 5, AlertOutOfStock},
+		{"normal a negativo", 3, -2, 0, AlertOutOfStock},
+		{"bajo a bajo no repite la alerta", 5, 3, 5, ""},
+		{"bajo a agotado", 3, 0, 5, AlertOutOfStock},
+		{"agotado a agotado no repite la alerta", 0, -1, 5, ""},
+		{"agotado a bajo no avisa", 0, 3, 5, ""},
+		{"agotado a normal no avisa", 0, 10, 5, ""},
+		{"bajo a normal no avisa", 4, 10, 5, ""},
+		{"sin umbral, normal a agotado", 1, 0, 0, AlertOutOfStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectStockAlert(tt.before, tt.after, tt.threshold); got != tt.want {
+				t.Errorf("DetectStockAlert(%d, %d, %d) = %q, se esperaba %q", tt.before, tt.after, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+// Una secuencia de movimientos avisa una vez por cruce y vuelve a avisar tras reponer
+func TestDetectStockAlertSequence(t *testing.T) {
+	const threshold = 5
+	movements := []struct {
+		quantity int
+		want     string
+	}{
+		{-4, ""},              // 10 -> 6
+		{-1, AlertLowStock},   // 6 -> 5
+		{-2, ""},              // 5 -> 3
+		{-3, AlertOutOfStock}, // 3 -> 0
+		{2, ""},               // 0 -> 2: sigue bajo, pero mejora
+		{10, ""},              // 2 -> 12: repuesto
+		{-8, AlertLowStock},   // 12 -> 4: segundo cruce
+		{-4, AlertOutOfStock}, // 4 -> 0
+	}
+
+	stock := 10
+	for i, movement := range movements {
+		after, err := ApplyMovement(stock, movement.quantity, false)
+		if err != nil {
+			t.Fatalf("movimiento %d: error inesperado: %v", i, err)
+		}
+		if got := DetectStockAlert(stock, after, threshold); got != movement.want {
+			t.Errorf("movimiento %d (%d -> %d) = %q, se esperaba %q", i, stock, after, got, movement.want)
+		}
+		stock = after
+	}
+}
@@ -2,7 +2,9 @@ package infraestructure
 
 import (
 	"encoding/json"
+	"errors"
 	"expresApi/src/products/application"
+	"expresApi/src/products/domain"
 	wsocket "expresApi/src/websocket"
 	"net/http"
 	"time"
@@ -22,7 +24,7 @@ type RequestBody struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
+	CategoryID  int     `json:"category_id"`
 	ImageURL    string  `json:"image_url"`
 }
 
@@ -33,7 +35,11 @@ func (ct_c *CreateProductController) Execute(c *gin.Context) {
 		return
 	}
 
-	err := ct_c.useCase.Execute(body.Name, body.Description, body.Price, body.Category, body.ImageURL)
+	err := ct_c.useCase.Execute(body.Name, body.Description, body.Price, body.CategoryID, body.ImageURL)
+	if errors.Is(err, domain.ErrCategoryNotFound) || errors.Is(err, domain.ErrCategoryInactive) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Categoría inválida", "detalles": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al guardar el producto", "detalles": err.Error()})
 		return
@@ -47,7 +53,7 @@ func (ct_c *CreateProductController) Execute(c *gin.Context) {
 			"name":        body.Name,
 			"description": body.Description,
 			"price":       body.Price,
-			"category":    body.Category,
+			"category_id": body.CategoryID,
 			"image_url":   body.ImageURL,
 			"action":      "creado",
 		},
@@ -2,7 +2,9 @@ package infraestructure
 
 import (
 	"encoding/json"
+	"errors"
 	"expresApi/src/products/application"
+	"expresApi/src/products/domain"
 	wsocket "expresApi/src/websocket"
 	"net/http"
 	"time"
@@ -26,6 +28,10 @@ func (d *DeleteProductController) Execute(c *gin.Context) {
 	}
 
 	err := d.useCase.Execute(id)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el producto", "detalles": err.Error()})
 		return
@@ -0,0 +1,37 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/products/application"
+	"expresApi/src/products/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetProductController struct {
+	useCase *application.GetProduct
+}
+
+func NewGetProductController(useCase *application.GetProduct) *GetProductController {
+	return &GetProductController{useCase: useCase}
+}
+
+func (gp_c *GetProductController) Execute(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	product, err := gp_c.useCase.Execute(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el producto", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
@@ -1,10 +1,12 @@
 package infraestructure
 
 import (
+	"database/sql"
 	"expresApi/src/config"
 	"expresApi/src/products/domain"
 	"fmt"
 	"log"
+	"strings"
 )
 
 type MySQL struct {
@@ -21,38 +23,223 @@ func NewMySQL() domain.IProduct {
 	return &MySQL{conn: conn}
 }
 
-func (mysql *MySQL) SaveProduct(name string, description string, price float64, category string, imageURL string) error {
-	query := "INSERT INTO products (name, description, price, category, image_url) VALUES (?, ?, ?, ?, ?)"
-	result, err := mysql.conn.ExecutePreparedQuery(query, name, description, price, category, imageURL)
+func (mysql *MySQL) SaveProduct(name string, description string, price float64, categoryID int, imageURL string) error {
+	query := "INSERT INTO products (name, description, price, category_id, image_url) VALUES (?, ?, ?, ?, ?)"
+	result, err := mysql.conn.ExecutePreparedQuery(query, name, description, price, categoryID, imageURL)
 	if err != nil {
 		return fmt.Errorf("Error al ejecutar la consulta: %v", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 1 {
-		log.Printf("[MySQL] - Producto guardado correctamente: Name:%s Description:%s Price:%f Category:%s ImageURL:%s", name, description, price, category, imageURL)
+		log.Printf("[MySQL] - Producto guardado correctamente: Name:%s Description:%s Price:%f CategoryID:%d ImageURL:%s", name, description, price, categoryID, imageURL)
 	} else {
 		log.Println("[MySQL] - No se insertó ninguna fila")
 	}
 	return nil
 }
 
-func (mysql *MySQL) GetAll() ([]domain.Product, error) {
-	query := "SELECT id, name, description, price, category, COALESCE(image_url, '') as image_url FROM products"
-	rows, err := mysql.conn.FetchRows(query)
+// El promedio de calificaciones sale de los comentarios; los productos sin comentarios quedan en 0
+const productFrom = ` FROM products p
+	JOIN categories c ON c.id = p.category_id
+	LEFT JOIN (SELECT product_id, AVG(rating) AS average_rating, COUNT(*) AS rating_count FROM comments GROUP BY product_id) r
+	ON r.product_id = p.id`
+
+const productColumns = "p.id, p.name, p.description, p.price, p.category_id, COALESCE(p.image_url, ''), COALESCE(r.average_rating, 0), COALESCE(r.rating_count, 0), p.stock, p.allow_backorder, p.reorder_threshold, " +
+	"c.id, c.name, COALESCE(c.description, ''), COALESCE(c.image_url, ''), c.is_active"
+
+var productSortColumns = map[string]string{
+	domain.ProductSortNewest: "p.id",
+	domain.ProductSortPrice:  "p.price",
+	domain.ProductSortName:   "p.name",
+	domain.ProductSortRating: "COALESCE(r.average_rating, 0)",
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(row rowScanner) (*domain.Product, error) {
+	var product domain.Product
+	var category domain.ProductCategory
+	err := row.Scan(&product.ID, &product.Name, &product.Description, &product.Price, &product.CategoryID, &product.ImageURL, &product.AverageRating, &product.RatingCount, &product.Stock, &product.AllowBackorder, &product.ReorderThreshold,
+		&category.ID, &category.Name, &category.Description, &category.ImageURL, &category.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	product.Category = &category
+	return &product, nil
+}
+
+func productFilters(query domain.ProductQuery) ([]string, []interface{}) {
+	// Los productos de una categoría desactivada no aparecen en el catálogo, aunque su detalle sigue disponible
+	conditions := []string{"c.is_active = TRUE"}
+	args := []interface{}{}
+
+	if query.CategoryID != 0 {
+		conditions = append(conditions, "p.category_id = ?")
+		args = append(args, query.CategoryID)
+	}
+	if query.Category != "" {
+		conditions = append(conditions, "c.name = ?")
+		args = append(args, query.Category)
+	}
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		conditions = append(conditions, "(p.name LIKE ? OR p.description LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	if query.MinPrice != nil {
+		conditions = append(conditions, "p.price >= ?")
+		args = append(args, *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		conditions = append(conditions, "p.price <= ?")
+		args = append(args, *query.MaxPrice)
+	}
+	if query.MinRating != nil {
+		conditions = append(conditions, "COALESCE(r.average_rating, 0) >= ?")
+		args = append(args, *query.MinRating)
+	}
+	return conditions, args
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func (mysql *MySQL) FindProducts(query domain.ProductQuery) ([]domain.Product, error) {
+	column, ok := productSortColumns[query.SortBy]
+	if !ok {
+		column = "p.id"
+	}
+	direction, comparator := "ASC", ">"
+	if query.SortDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	conditions, args := productFilters(query)
+	if query.After != nil {
+		// Paginación por cursor (keyset): el ID desempata valores repetidos
+		if column == "p.id" {
+			conditions = append(conditions, fmt.Sprintf("p.id %s ?", comparator))
+			args = append(args, query.After.ID)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s %s ? OR (%s = ? AND p.id %s ?))", column, comparator, column, comparator))
+			args = append(args, query.After.Value, query.After.Value, query.After.ID)
+		}
+	}
+
+	sqlQuery := "SELECT " + productColumns + productFrom + whereClause(conditions)
+	if column == "p.id" {
+		sqlQuery += fmt.Sprintf(" ORDER BY p.id %s", direction)
+	} else {
+		sqlQuery += fmt.Sprintf(" ORDER BY %s %s, p.id %s", column, direction, direction)
+	}
+	sqlQuery += " LIMIT ?"
+	args = append(args, query.Limit)
+	if query.After == nil && query.Offset > 0 {
+		sqlQuery += " OFFSET ?"
+		args = append(args, query.Offset)
+	}
+
+	rows, err := mysql.conn.FetchRows(sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("Error al ejecutar la consulta SELECT: %v", err)
 	}
 	defer rows.Close()
 
-	var products []domain.Product
+	products := []domain.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Error al escanear la fila: %v", err)
+		}
+		products = append(products, *product)
+	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error iterando sobre las filas: %v", err)
+	}
+	return products, nil
+}
+
+func (mysql *MySQL) CountProducts(query domain.ProductQuery) (int, error) {
+	conditions, args := productFilters(query)
+	row, err := mysql.conn.FetchRow("SELECT COUNT(*)"+productFrom+whereClause(conditions), args...)
+	if err != nil {
+		return 0, fmt.Errorf("Error al ejecutar la consulta: %v", err)
+	}
+
+	var total int
+	if err := row.Scan(&total); err != nil {
+		return 0, fmt.Errorf("Error al contar los productos: %v", err)
+	}
+	return total, nil
+}
+
+func (mysql *MySQL) GetByID(id int32) (*domain.Product, error) {
+	query := "SELECT " + productColumns + productFrom + " WHERE p.id = ?"
+	row, err := mysql.conn.FetchRow(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("Error al ejecutar la consulta SELECT: %v", err)
+	}
+
+	product, err := scanProduct(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error al escanear la fila: %v", err)
+	}
+	return product, nil
+}
+
+func (mysql *MySQL) SetAllowBackorder(id int32, allow bool) error {
+	// SELECT previo: RowsAffected es 0 también cuando el valor no cambia
+	if _, err := mysql.GetByID(id); err != nil {
+		return err
+	}
+	if _, err := mysql.conn.ExecutePreparedQuery("UPDATE products SET allow_backorder = ? WHERE id = ?", allow, id); err != nil {
+		return fmt.Errorf("Error al ejecutar la consulta UPDATE: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQL) SetReorderThreshold(id int32, threshold int) error {
+	if _, err := mysql.GetByID(id); err != nil {
+		return err
+	}
+	if _, err := mysql.conn.ExecutePreparedQuery("UPDATE products SET reorder_threshold = ? WHERE id = ?", threshold, id); err != nil {
+		return fmt.Errorf("Error al ejecutar la consulta UPDATE: %v", err)
+	}
+	return nil
+}
+
+// FindBelowThreshold incluye los productos de categorías desactivadas: siguen ocupando el almacén
+func (mysql *MySQL) FindBelowThreshold() ([]domain.Product, error) {
+	query := "SELECT " + productColumns + productFrom + " WHERE p.stock <= p.reorder_threshold OR p.stock <= 0 ORDER BY p.stock ASC, p.id ASC"
+	rows, err := mysql.conn.FetchRows(query)
+	if err != nil {
+		return nil, fmt.Errorf("Error al ejecutar la consulta SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	products := []domain.Product{}
 	for rows.Next() {
-		var product domain.Product
-		if err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.Price, &product.Category, &product.ImageURL); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("Error al escanear la fila: %v", err)
 		}
-		products = append(products, product)
+		products = append(products, *product)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -70,16 +257,16 @@ func (mysql *MySQL) Delete(id string) error {
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("No se encontró el producto con ID: %s", id)
+		return fmt.Errorf("%w: ID %s", domain.ErrProductNotFound, id)
 	}
 
 	log.Printf("[MySQL] - Producto eliminado correctamente con ID: %s", id)
 	return nil
 }
 
-func (mysql *MySQL) Update(id string, name string, description string, price float64, category string, imageURL string) error {
-	query := "UPDATE products SET name = ?, description = ?, price = ?, category = ?, image_url = ? WHERE id = ?"
-	result, err := mysql.conn.ExecutePreparedQuery(query, name, description, price, category, imageURL, id)
+func (mysql *MySQL) Update(id string, name string, description string, price float64, categoryID int, imageURL string) error {
+	query := "UPDATE products SET name = ?, description = ?, price = ?, category_id = ?, image_url = ? WHERE id = ?"
+	result, err := mysql.conn.ExecutePreparedQuery(query, name, description, price, categoryID, imageURL, id)
 	if err != nil {
 		return fmt.Errorf("Error al ejecutar la consulta UPDATE: %v", err)
 	}
@@ -0,0 +1,101 @@
+package infraestructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"expresApi/src/products/domain"
+	userDomain "expresApi/src/users/domain"
+	wsocket "expresApi/src/websocket"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// StockAlertNotifiers reparte cada alerta entre todos los canales configurados
+type StockAlertNotifiers []domain.IStockAlertNotifier
+
+var _ domain.IStockAlertNotifier = StockAlertNotifiers(nil)
+
+func (n StockAlertNotifiers) Notify(alert domain.StockAlert) {
+	for _, notifier := range n {
+		notifier.Notify(alert)
+	}
+}
+
+// WebSocketStockAlertNotifier envía la alerta por WebSocket solo a quienes gestionan el inventario;
+// el stock y los umbrales no se difunden a las conexiones anónimas del catálogo
+type WebSocketStockAlertNotifier struct{}
+
+var _ domain.IStockAlertNotifier = WebSocketStockAlertNotifier{}
+
+func (WebSocketStockAlertNotifier) Notify(alert domain.StockAlert) {
+	wsMessage := map[string]interface{}{
+		"type":      alert.Type,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"data":      alert,
+	}
+
+	if messageBytes, err := json.Marshal(wsMessage); err == nil {
+		wsocket.BroadcastToPermission(userDomain.PermissionProductsWrite, messageBytes)
+	}
+}
+
+// WebhookStockAlertNotifier envía la alerta por POST a cada URL configurada.
+// Con secreto, el cuerpo va firmado con HMAC-SHA256 en la cabecera X-Signature-256.
+type WebhookStockAlertNotifier struct {
+	urls   []string
+	secret string
+	client *http.Client
+}
+
+var _ domain.IStockAlertNotifier = (*WebhookStockAlertNotifier)(nil)
+
+func NewWebhookStockAlertNotifier(urls []string, secret string, timeout time.Duration) *WebhookStockAlertNotifier {
+	return &WebhookStockAlertNotifier{urls: urls, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// Notify entrega la alerta en segundo plano para no retrasar la respuesta del movimiento
+func (w *WebhookStockAlertNotifier) Notify(alert domain.StockAlert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.Printf("[Webhook] - Error al serializar la alerta %s: %v", alert.Type, err)
+		return
+	}
+
+	for _, url := range w.urls {
+		go func(url string) {
+			if err := w.send(url, alert.Type, body); err != nil {
+				log.Printf("[Webhook] - Error al enviar %s del producto ID: %d a %s: %v", alert.Type, alert.ProductID, url, err)
+			}
+		}(url)
+	}
+}
+
+func (w *WebhookStockAlertNotifier) send(url string, event string, body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Event", event)
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		request.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("respuesta %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/products/domain"
+	"fmt"
+	"log"
+	"time"
+)
+
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+type MySQLStockLedger struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IStockLedger = (*MySQLStockLedger)(nil)
+
+func NewMySQLStockLedger(conn *config.Conn_MySQL) domain.IStockLedger {
+	return &MySQLStockLedger{conn: conn}
+}
+
+func (mysql *MySQLStockLedger) Record(movement *domain.StockMovement) error {
+	tx, err := mysql.conn.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE serializa los movimientos concurrentes del mismo producto hasta el COMMIT
+	var stock, threshold int
+	var allowBackorder bool
+	err = tx.QueryRow("SELECT stock, allow_backorder, reorder_threshold FROM products WHERE id = ? FOR UPDATE", movement.ProductID).Scan(&stock, &allowBackorder, &threshold)
+	if err == sql.ErrNoRows {
+		return domain.ErrProductNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error al bloquear el producto: %v", err)
+	}
+
+	stockAfter, err := domain.ApplyMovement(stock, movement.Quantity, allowBackorder)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE products SET stock = ? WHERE id = ?", stockAfter, movement.ProductID); err != nil {
+		return fmt.Errorf("error al actualizar el stock: %v", err)
+	}
+
+	var userID interface{}
+	if movement.UserID != 0 {
+		userID = movement.UserID
+	}
+	createdAt := time.Now().UTC()
+	result, err := tx.Exec(
+		"INSERT INTO stock_movements (product_id, type, quantity, reason, user_id, stock_after, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		movement.ProductID, movement.Type, movement.Quantity, movement.Reason, userID, stockAfter, createdAt.Format(dateTimeLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("error al registrar el movimiento: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar el movimiento: %v", err)
+	}
+
+	movement.ID, _ = result.LastInsertId()
+	movement.StockAfter = stockAfter
+	movement.CreatedAt = createdAt
+	// El bloqueo garantiza que el stock anterior es exacto, así que cada cruce se detecta una sola vez
+	movement.Alert = domain.DetectStockAlert(stock, stockAfter, threshold)
+	log.Printf("[MySQL] - Movimiento %s de %d unidades en el producto ID: %d, stock: %d", movement.Type, movement.Quantity, movement.ProductID, stockAfter)
+	return nil
+}
+
+func (mysql *MySQLStockLedger) ListByProduct(productID int32, limit int, offset int) ([]domain.StockMovement, error) {
+	query := `SELECT id, product_id, type, quantity, reason, COALESCE(user_id, 0), stock_after, created_at
+		FROM stock_movements WHERE product_id = ? ORDER BY id DESC LIMIT ? OFFSET ?`
+	rows, err := mysql.conn.FetchRows(query, productID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("Error al ejecutar la consulta SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	movements := []domain.StockMovement{}
+	for rows.Next() {
+		var movement domain.StockMovement
+		var createdAt string
+		if err := rows.Scan(&movement.ID, &movement.ProductID, &movement.Type, &movement.Quantity, &movement.Reason, &movement.UserID, &movement.StockAfter, &createdAt); err != nil {
+			return nil, fmt.Errorf("Error al escanear la fila: %v", err)
+		}
+		movement.CreatedAt, _ = time.Parse(dateTimeLayout, createdAt)
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error iterando sobre las filas: %v", err)
+	}
+	return movements, nil
+}
+
+func (mysql *MySQLStockLedger) CountByProduct(productID int32) (int, error) {
+	row, err := mysql.conn.FetchRow("SELECT COUNT(*) FROM stock_movements WHERE product_id = ?", productID)
+	if err != nil {
+		return 0, fmt.Errorf("Error al ejecutar la consulta: %v", err)
+	}
+
+	var total int
+	if err := row.Scan(&total); err != nil {
+		return 0, fmt.Errorf("Error al contar los movimientos: %v", err)
+	}
+	return total, nil
+}
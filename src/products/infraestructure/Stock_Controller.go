@@ -0,0 +1,208 @@
+package infraestructure
+
+import (
+	"encoding/json"
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/products/application"
+	"expresApi/src/products/domain"
+	userDomain "expresApi/src/users/domain"
+	wsocket "expresApi/src/websocket"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockController struct {
+	record     *application.RecordStockMovement
+	list       *application.ListStockMovements
+	backorders *application.SetBackorders
+	threshold  *application.SetReorderThreshold
+	lowStock   *application.ListLowStock
+}
+
+func NewStockController(record *application.RecordStockMovement, list *application.ListStockMovements, backorders *application.SetBackorders,
+	threshold *application.SetReorderThreshold, lowStock *application.ListLowStock) *StockController {
+	return &StockController{record: record, list: list, backorders: backorders, threshold: threshold, lowStock: lowStock}
+}
+
+type StockMovementRequestBody struct {
+	Type     string `json:"type"`
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+// Record registra un movimiento de inventario a nombre del usuario autenticado
+func (s *StockController) Record(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var body StockMovementRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	var userID int32
+	if user, ok := middleware.CurrentUser(c); ok {
+		userID = user.ID
+	}
+
+	movement, err := s.record.Execute(productID, body.Type, body.Quantity, body.Reason, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrInvalidMovementType), errors.Is(err, application.ErrInvalidMovementQuantity),
+			errors.Is(err, application.ErrMovementReasonRequired), errors.Is(err, application.ErrMovementReasonTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al registrar el movimiento", "detalles": err.Error()})
+		}
+		return
+	}
+
+	// Notificación WebSocket solo para quienes gestionan el inventario: el stock no es público
+	wsMessage := map[string]interface{}{
+		"type":      "stock_updated",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"data": map[string]interface{}{
+			"id":       movement.ProductID,
+			"movement": movement.Type,
+			"quantity": movement.Quantity,
+			"stock":    movement.StockAfter,
+			"action":   "actualizado",
+		},
+	}
+
+	if messageBytes, err := json.Marshal(wsMessage); err == nil {
+		wsocket.BroadcastToPermission(userDomain.PermissionProductsWrite, messageBytes)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Movimiento registrado correctamente", "movement": movement})
+}
+
+// History devuelve el libro de movimientos del producto. Parámetros: limit, offset
+func (s *StockController) History(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit debe ser un número"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset debe ser un número"})
+		return
+	}
+
+	page, err := s.list.Execute(productID, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrInvalidProductPage):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los movimientos", "detalles": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   page.Movements,
+		"total":  page.Total,
+		"limit":  page.Limit,
+		"offset": page.Offset,
+	})
+}
+
+// SetBackorders permite o impide que el stock del producto quede en negativo
+func (s *StockController) SetBackorders(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		AllowBackorder *bool `json:"allow_backorder"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.AllowBackorder == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El campo allow_backorder es requerido"})
+		return
+	}
+
+	product, err := s.backorders.Execute(productID, *body.AllowBackorder)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el producto", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Producto actualizado correctamente", "product": product})
+}
+
+// SetReorderThreshold cambia el stock a partir del cual se avisa que el producto está bajo
+func (s *StockController) SetReorderThreshold(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		ReorderThreshold *int `json:"reorder_threshold"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.ReorderThreshold == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El campo reorder_threshold es requerido"})
+		return
+	}
+
+	product, err := s.threshold.Execute(productID, *body.ReorderThreshold)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrInvalidReorderThreshold):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el producto", "detalles": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Producto actualizado correctamente", "product": product})
+}
+
+// LowStock lista los productos con stock bajo o agotado, empezando por el menor stock
+func (s *StockController) LowStock(c *gin.Context) {
+	products, err := s.lowStock.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los productos", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": products, "total": len(products)})
+}
+
+// parseProductID lee el :id de la ruta y responde 400 si no es válido
+func parseProductID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de producto inválido"})
+		return 0, false
+	}
+	return int32(id), true
+}
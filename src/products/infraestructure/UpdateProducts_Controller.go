@@ -2,7 +2,9 @@ package infraestructure
 
 import (
 	"encoding/json"
+	"errors"
 	"expresApi/src/products/application"
+	"expresApi/src/products/domain"
 	wsocket "expresApi/src/websocket"
 	"net/http"
 	"time"
@@ -22,7 +24,7 @@ type UpdateRequestBody struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
+	CategoryID  int     `json:"category_id"`
 	ImageURL    string  `json:"image_url"`
 }
 
@@ -39,7 +41,11 @@ func (u *UpdateProductController) Execute(c *gin.Context) {
 		return
 	}
 
-	err := u.useCase.Execute(id, body.Name, body.Description, body.Price, body.Category, body.ImageURL)
+	err := u.useCase.Execute(id, body.Name, body.Description, body.Price, body.CategoryID, body.ImageURL)
+	if errors.Is(err, domain.ErrCategoryNotFound) || errors.Is(err, domain.ErrCategoryInactive) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Categoría inválida", "detalles": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el producto", "detalles": err.Error()})
 		return
@@ -54,7 +60,7 @@ func (u *UpdateProductController) Execute(c *gin.Context) {
 			"name":        body.Name,
 			"description": body.Description,
 			"price":       body.Price,
-			"category":    body.Category,
+			"category_id": body.CategoryID,
 			"image_url":   body.ImageURL,
 			"action":      "actualizado",
 		},
@@ -1,8 +1,13 @@
 package infraestructure
 
 import (
+	"errors"
 	"expresApi/src/products/application"
+	"expresApi/src/products/domain"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,12 +20,92 @@ func NewViewProductController(useCase *application.ViewProduct) *ViewProductCont
 	return &ViewProductController{useCase: useCase}
 }
 
+// Execute lista productos con filtros, orden y paginación.
+// Parámetros: category_id, category (nombre), q, min_price, max_price, min_rating,
+// sort (newest|price|name|rating), order (asc|desc), limit, offset, cursor
 func (et_c *ViewProductController) Execute(c *gin.Context) {
-	products, err := et_c.useCase.Execute()
+	query, err := parseProductQuery(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parámetros de búsqueda inválidos", "detalles": err.Error()})
+		return
+	}
+
+	page, err := et_c.useCase.Execute(query, c.Query("cursor"))
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidProductSort) || errors.Is(err, application.ErrInvalidProductCursor) ||
+			errors.Is(err, application.ErrInvalidProductPage) || errors.Is(err, application.ErrInvalidProductFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los productos", "detalles": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, products)
+	c.JSON(http.StatusOK, gin.H{
+		"data":        page.Products,
+		"total":       page.Total,
+		"limit":       page.Limit,
+		"offset":      page.Offset,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+func parseProductQuery(c *gin.Context) (domain.ProductQuery, error) {
+	query := domain.ProductQuery{
+		Category: strings.TrimSpace(c.Query("category")),
+		Search:   strings.TrimSpace(c.Query("q")),
+		SortBy:   c.Query("sort"),
+	}
+
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+		query.SortDesc = application.DefaultSortDesc(query.SortBy)
+	case "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		return query, errors.New("order debe ser asc o desc")
+	}
+
+	var err error
+	if value := c.Query("category_id"); value != "" {
+		if query.CategoryID, err = strconv.Atoi(value); err != nil {
+			return query, errors.New("category_id debe ser un número")
+		}
+	}
+	if query.MinPrice, err = parseFloatParam(c.Query("min_price")); err != nil {
+		return query, errors.New("min_price debe ser un número")
+	}
+	if query.MaxPrice, err = parseFloatParam(c.Query("max_price")); err != nil {
+		return query, errors.New("max_price debe ser un número")
+	}
+	if query.MinRating, err = parseFloatParam(c.Query("min_rating")); err != nil {
+		return query, errors.New("min_rating debe ser un número")
+	}
+
+	if value := c.Query("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, errors.New("limit debe ser un número")
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		if query.Offset, err = strconv.Atoi(value); err != nil {
+			return query, errors.New("offset debe ser un número")
+		}
+	}
+	return query, nil
+}
+
+func parseFloatParam(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return nil, errors.New("número no finito")
+	}
+	return &parsed, nil
 }
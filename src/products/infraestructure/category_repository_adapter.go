@@ -0,0 +1,35 @@
+package infraestructure
+
+import (
+	categoryDomain "expresApi/src/categories/domain"
+	categoryInfra "expresApi/src/categories/infrastructure"
+	"expresApi/src/products/domain"
+)
+
+// CategoryRepositoryAdapter adapta el repositorio de categorías para validar la categoría de los productos
+type CategoryRepositoryAdapter struct {
+	repo categoryDomain.ICategoryRepository
+}
+
+var _ domain.IProductCategories = (*CategoryRepositoryAdapter)(nil)
+
+// NewCategoryRepositoryAdapter crea una nueva instancia del adaptador
+func NewCategoryRepositoryAdapter() *CategoryRepositoryAdapter {
+	return &CategoryRepositoryAdapter{repo: categoryInfra.NewMySQLCategoryRepository()}
+}
+
+// GetByID devuelve nil si no hay una categoría con ese ID
+func (c *CategoryRepositoryAdapter) GetByID(id int) (*domain.ProductCategory, error) {
+	category, err := c.repo.GetCategoryByID(id)
+	if err != nil || category == nil {
+		return nil, err
+	}
+
+	return &domain.ProductCategory{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		ImageURL:    category.ImageURL,
+		IsActive:    category.IsActive,
+	}, nil
+}
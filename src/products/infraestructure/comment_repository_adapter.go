@@ -3,6 +3,8 @@ package infraestructure
 import (
 	"database/sql"
 	commentInfra "expresApi/src/comments/infrastructure"
+	"expresApi/src/products/domain"
+	"math"
 )
 
 // CommentRepositoryAdapter adapta el repositorio de comentarios para usar en productos
@@ -10,6 +12,8 @@ type CommentRepositoryAdapter struct {
 	repo *commentInfra.MySQLCommentRepository
 }
 
+var _ domain.IProductRatings = (*CommentRepositoryAdapter)(nil)
+
 // NewCommentRepositoryAdapter crea una nueva instancia del adaptador
 func NewCommentRepositoryAdapter(db *sql.DB) *CommentRepositoryAdapter {
 	repo := commentInfra.NewMySQLCommentRepository(db)
@@ -22,3 +26,17 @@ func NewCommentRepositoryAdapter(db *sql.DB) *CommentRepositoryAdapter {
 func (c *CommentRepositoryAdapter) DeleteByProductID(productID int) error {
 	return c.repo.DeleteByProductID(productID)
 }
+
+// GetRatingSummary obtiene el promedio y la distribución de calificaciones del producto
+func (c *CommentRepositoryAdapter) GetRatingSummary(productID int32) (*domain.RatingSummary, error) {
+	stats, err := c.repo.GetStats(int(productID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RatingSummary{
+		AverageRating: math.Round(stats.AverageRating*100) / 100,
+		TotalComments: stats.TotalComments,
+		Distribution:  stats.RatingCounts,
+	}, nil
+}
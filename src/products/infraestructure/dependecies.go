@@ -1,9 +1,34 @@
 package infraestructure
 
 import (
+	"expresApi/src/config"
+	"expresApi/src/products/domain"
 	"log"
+	"strings"
+	"time"
 )
 
+// NewStockAlertNotifier envía las alertas de stock por WebSocket y, si hay URLs en
+// STOCK_ALERT_WEBHOOK_URLS (separadas por comas), también por webhook
+func NewStockAlertNotifier() domain.IStockAlertNotifier {
+	notifiers := StockAlertNotifiers{WebSocketStockAlertNotifier{}}
+
+	var urls []string
+	for _, url := range strings.Split(config.GetEnv("STOCK_ALERT_WEBHOOK_URLS", ""), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) > 0 {
+		notifiers = append(notifiers, NewWebhookStockAlertNotifier(
+			urls,
+			config.GetEnv("STOCK_ALERT_WEBHOOK_SECRET", ""),
+			config.GetEnvDuration("STOCK_ALERT_WEBHOOK_TIMEOUT", 5*time.Second),
+		))
+	}
+	return notifiers
+}
+
 func Init() {
 	mysqlRepo := NewMySQL()
 
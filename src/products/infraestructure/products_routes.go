@@ -2,8 +2,10 @@ package infraestructure
 
 import (
 	"expresApi/src/config"
+	"expresApi/src/config/middleware"
 	"expresApi/src/products/application"
 	"expresApi/src/products/domain"
+	userDomain "expresApi/src/users/domain"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -12,7 +14,7 @@ import (
 func SetupRouter(repo domain.IProduct) *gin.Engine {
 	r := gin.Default()
 
-	CreateProduct := application.NewCreateProduct(repo)
+	CreateProduct := application.NewCreateProduct(repo, NewCategoryRepositoryAdapter())
 	createProductController := NewCreateProductController(CreateProduct)
 
 	viewProduct := application.NewViewProduct(repo)
@@ -25,7 +27,7 @@ func SetupRouter(repo domain.IProduct) *gin.Engine {
 }
 
 // Nueva función para registrar rutas en un grupo
-func RegisterRoutes(r *gin.RouterGroup, repo domain.IProduct) {
+func RegisterRoutes(r *gin.RouterGroup, repo domain.IProduct, auth gin.HandlerFunc) {
 	// Obtener conexión a la base de datos para comentarios
 	dbConfig := config.GetDBPool()
 	if dbConfig.Err != "" {
@@ -34,24 +36,51 @@ func RegisterRoutes(r *gin.RouterGroup, repo domain.IProduct) {
 
 	// Crear adaptador de repositorio de comentarios
 	var commentRepo application.CommentRepository
+	var ratings domain.IProductRatings
 	if dbConfig.Err == "" {
-		commentRepo = NewCommentRepositoryAdapter(dbConfig.DB)
+		adapter := NewCommentRepositoryAdapter(dbConfig.DB)
+		commentRepo = adapter
+		ratings = adapter
 	}
 
-	CreateProduct := application.NewCreateProduct(repo)
+	categories := NewCategoryRepositoryAdapter()
+
+	CreateProduct := application.NewCreateProduct(repo, categories)
 	createProductController := NewCreateProductController(CreateProduct)
 
 	viewProduct := application.NewViewProduct(repo)
 	viewProductController := NewViewProductController(viewProduct)
 
-	updateProduct := application.NewUpdateProduct(repo)
+	updateProduct := application.NewUpdateProduct(repo, categories)
 	updateProductController := NewUpdateProductController(updateProduct)
 
+	getProduct := application.NewGetProduct(repo, ratings)
+	getProductController := NewGetProductController(getProduct)
+
 	deleteProduct := application.NewDeleteProduct(repo, commentRepo)
 	deleteProductController := NewDeleteProductController(deleteProduct)
 
-	r.POST("/products", createProductController.Execute)
+	ledger := NewMySQLStockLedger(dbConfig)
+	stockController := NewStockController(
+		application.NewRecordStockMovement(repo, ledger, NewStockAlertNotifier()),
+		application.NewListStockMovements(repo, ledger),
+		application.NewSetBackorders(repo),
+		application.NewSetReorderThreshold(repo),
+		application.NewListLowStock(repo),
+	)
+
+	canWrite := middleware.RequirePermission(userDomain.PermissionProductsWrite)
+
+	r.POST("/products", auth, canWrite, createProductController.Execute)
 	r.GET("/products", viewProductController.Execute)
-	r.PUT("/products/:id", updateProductController.Execute)
-	r.DELETE("/products/:id", deleteProductController.Execute)
+	r.GET("/products/:id", getProductController.Execute)
+	r.PUT("/products/:id", auth, canWrite, updateProductController.Execute)
+	r.DELETE("/products/:id", auth, canWrite, deleteProductController.Execute)
+
+	// Inventario: el stock solo cambia mediante movimientos que quedan en el historial
+	r.POST("/products/:id/stock-movements", auth, canWrite, stockController.Record)
+	r.GET("/products/:id/stock-movements", auth, canWrite, stockController.History)
+	r.PUT("/products/:id/backorders", auth, canWrite, stockController.SetBackorders)
+	r.PUT("/products/:id/reorder-threshold", auth, canWrite, stockController.SetReorderThreshold)
+	r.GET("/products/low-stock", auth, canWrite, stockController.LowStock)
 }
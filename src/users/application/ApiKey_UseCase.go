@@ -0,0 +1,182 @@
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"expresApi/src/users/domain"
+	"log"
+	"strings"
+	"time"
+)
+
+// Las API keys tienen el formato eak_<prefijo>_<secreto>; el prefijo identifica la key sin revelarla
+const apiKeyMarker = "eak_"
+
+var (
+	ErrApiKeyNameRequired = errors.New("el nombre de la API key es requerido")
+	ErrApiKeyExpiry       = errors.New("la fecha de expiración debe ser futura")
+	ErrInvalidApiKey      = errors.New("API key inválida, revocada o expirada")
+)
+
+// ApiKeyScopeError indica que se pidió un scope que el usuario no tiene
+type ApiKeyScopeError struct {
+	Scope string
+}
+
+func (e *ApiKeyScopeError) Error() string {
+	return "scope no permitido para este usuario: " + e.Scope
+}
+
+// IsApiKey indica si un bearer token tiene el formato de una API key
+func IsApiKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyMarker)
+}
+
+type CreateApiKey struct {
+	db   domain.IUser
+	keys domain.IApiKey
+}
+
+func NewCreateApiKey(db domain.IUser, keys domain.IApiKey) *CreateApiKey {
+	return &CreateApiKey{db: db, keys: keys}
+}
+
+// Execute crea una API key y devuelve el valor en claro (solo se muestra una vez).
+// Los scopes deben ser permisos que el usuario ya tiene; sin scopes la key hereda todos sus permisos.
+func (ck *CreateApiKey) Execute(userID int32, name string, scopes []string, expiresAt *time.Time) (string, *domain.ApiKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, ErrApiKeyNameRequired
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, ErrApiKeyExpiry
+	}
+
+	permissions, err := ck.db.GetUserPermissions(userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := validateScopes(scopes, permissions); err != nil {
+		return "", nil, err
+	}
+
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", nil, err
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+	raw := apiKeyMarker + prefix + "_" + secret
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+	key := &domain.ApiKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   HashToken(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := ck.keys.Save(key); err != nil {
+		return "", nil, err
+	}
+
+	log.Printf("[Auth] - API key creada: ID: %d Prefijo: %s Usuario ID: %d", key.ID, prefix, userID)
+	return raw, key, nil
+}
+
+func validateScopes(scopes []string, permissions []string) error {
+	allowed := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		allowed[permission] = true
+	}
+	for _, scope := range scopes {
+		if !allowed[scope] {
+			return &ApiKeyScopeError{Scope: scope}
+		}
+	}
+	return nil
+}
+
+type ListApiKeys struct {
+	keys domain.IApiKey
+}
+
+func NewListApiKeys(keys domain.IApiKey) *ListApiKeys {
+	return &ListApiKeys{keys: keys}
+}
+
+func (lk *ListApiKeys) Execute(userID int32) ([]domain.ApiKey, error) {
+	return lk.keys.ListByUser(userID)
+}
+
+type RevokeApiKey struct {
+	keys domain.IApiKey
+}
+
+func NewRevokeApiKey(keys domain.IApiKey) *RevokeApiKey {
+	return &RevokeApiKey{keys: keys}
+}
+
+func (rk *RevokeApiKey) Execute(userID int32, id int64) error {
+	revoked, err := rk.keys.Revoke(userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.ErrApiKeyNotFound
+	}
+
+	log.Printf("[Auth] - API key revocada: ID: %d Usuario ID: %d", id, userID)
+	return nil
+}
+
+// ApiKeyAuthenticator valida las API keys recibidas como bearer token
+type ApiKeyAuthenticator struct {
+	keys domain.IApiKey
+}
+
+func NewApiKeyAuthenticator(keys domain.IApiKey) *ApiKeyAuthenticator {
+	return &ApiKeyAuthenticator{keys: keys}
+}
+
+// Authenticate busca la key por su hash, comprueba que siga vigente y registra el último uso
+func (ka *ApiKeyAuthenticator) Authenticate(raw string, clientIP string) (*domain.ApiKey, error) {
+	key, err := ka.keys.GetByHash(HashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || !key.IsActive() {
+		return nil, ErrInvalidApiKey
+	}
+
+	if err := ka.keys.TouchLastUsed(key.ID, clientIP); err != nil {
+		log.Printf("[Auth] - No se pudo registrar el uso de la API key %d: %v", key.ID, err)
+	}
+	return key, nil
+}
+
+// ScopePermissions limita los permisos del usuario a los scopes de la key
+func ScopePermissions(permissions []string, scopes []string) []string {
+	if len(scopes) == 0 {
+		return permissions
+	}
+	allowed := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		allowed[scope] = true
+	}
+	effective := []string{}
+	for _, permission := range permissions {
+		if allowed[permission] {
+			effective = append(effective, permission)
+		}
+	}
+	return effective
+}
@@ -0,0 +1,17 @@
+package application
+
+// AuthError es un error de autenticación con un código legible por máquinas
+type AuthError struct {
+	Code    string
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrInvalidCredentials = &AuthError{Code: "INVALID_CREDENTIALS", Message: "credenciales inválidas"}
+	ErrAccountDeactivated = &AuthError{Code: "ACCOUNT_DEACTIVATED", Message: "la cuenta está desactivada"}
+	ErrEmailNotVerified   = &AuthError{Code: "EMAIL_NOT_VERIFIED", Message: "debes verificar tu email antes de iniciar sesión"}
+)
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // registra el decodificador GIF
+	_ "image/jpeg" // registra el decodificador JPEG
+	"image/png"
+)
+
+// Límite de dimensiones del original; evita decodificar imágenes enormes que caben en pocos bytes
+const maxAvatarDimension = 4096
+
+// resizeAvatar recorta la imagen al cuadrado central y devuelve un PNG por cada tamaño pedido
+func resizeAvatar(content []byte, sizes []int) (map[int][]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrAvatarInvalidImage
+	}
+	if config.Width > maxAvatarDimension || config.Height > maxAvatarDimension {
+		return nil, ErrAvatarTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrAvatarInvalidImage
+	}
+
+	square := cropSquare(src)
+	resized := make(map[int][]byte, len(sizes))
+	for _, size := range sizes {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, scaleBox(square, size)); err != nil {
+			return nil, err
+		}
+		resized[size] = buf.Bytes()
+	}
+	return resized, nil
+}
+
+// cropSquare copia el cuadrado central a una imagen RGBA, lo que además descarta metadatos del original
+func cropSquare(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), src, origin, draw.Src)
+	return square
+}
+
+// scaleBox redimensiona promediando los píxeles de origen que cubre cada píxel de destino;
+// al ampliar equivale a vecino más cercano
+func scaleBox(src *image.RGBA, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	side := src.Bounds().Dx()
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pixel := src.RGBAAt(sx, sy)
+					r += uint32(pixel.R)
+					g += uint32(pixel.G)
+					b += uint32(pixel.B)
+					a += uint32(pixel.A)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+)
+
+var ErrCurrentPasswordMismatch = errors.New("la contraseña actual es incorrecta")
+
+type ChangePassword struct {
+	db       domain.IUser
+	sessions *SessionService
+	policy   *PasswordPolicy
+	hashing  *PasswordHashing
+}
+
+func NewChangePassword(db domain.IUser, sessions *SessionService, policy *PasswordPolicy, hashing *PasswordHashing) *ChangePassword {
+	return &ChangePassword{db: db, sessions: sessions, policy: policy, hashing: hashing}
+}
+
+// Execute verifica la contraseña actual, guarda el nuevo hash y cierra las sesiones existentes
+func (cp *ChangePassword) Execute(id int32, currentPassword string, newPassword string) error {
+	if newPassword == "" {
+		return ErrPasswordRequired
+	}
+
+	user, err := cp.db.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := cp.hashing.Verify(user.Password, currentPassword); !ok {
+		return ErrCurrentPasswordMismatch
+	}
+	if err := cp.policy.Validate("newPassword", newPassword, user.UserName, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := cp.hashing.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := cp.db.UpdateUser(id, domain.UserChanges{Password: &hashedPassword}); err != nil {
+		return err
+	}
+
+	return cp.sessions.RevokeAllForUser(id)
+}
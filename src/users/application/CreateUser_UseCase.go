@@ -2,30 +2,55 @@ package application
 
 import (
 	"expresApi/src/users/domain"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 type CreateUser struct {
-	db domain.IUser
+	db           domain.IUser
+	verification *EmailVerificationService
+	policy       *PasswordPolicy
+	hashing      *PasswordHashing
 }
 
-func NewCreateUser(db domain.IUser) *CreateUser {
-	return &CreateUser{db: db}
+func NewCreateUser(db domain.IUser, verification *EmailVerificationService, policy *PasswordPolicy, hashing *PasswordHashing) *CreateUser {
+	return &CreateUser{db: db, verification: verification, policy: policy, hashing: hashing}
 }
 
 func (cu *CreateUser) Execute(userName string, email string, password string) error {
+	if err := normalizeField(&userName, ErrUserNameRequired); err != nil {
+		return err
+	}
+	if err := normalizeField(&email, ErrEmailRequired); err != nil {
+		return err
+	}
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	if err := cu.policy.Validate("password", password, userName, email); err != nil {
+		return err
+	}
+
+	// Verificar que el username y el email no estén en uso
+	if err := ensureUnique(cu.db, 0, &userName, &email); err != nil {
+		return err
+	}
+
 	// Generar hash de la contraseña
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := cu.hashing.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	// Los usuarios nuevos están activos (estado = true) pero pendientes de verificar su email
+	// y reciben el rol de cliente en la misma transacción
+	id, err := cu.db.SaveUser(userName, email, hashedPassword, domain.RoleCustomer, nil)
 	if err != nil {
 		return err
 	}
 
-	// Por defecto, los usuarios nuevos están activos (estado = true)
-	err = cu.db.SaveUser(userName, email, string(hashedPassword), true)
+	user, err := cu.db.GetByID(id)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return cu.verification.Send(user)
 }
@@ -2,16 +2,67 @@ package application
 
 import (
 	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"time"
 )
 
+// Autor con el que quedan los comentarios de un usuario eliminado
+const DeletedUserPlaceholder = "Usuario eliminado"
+
+// DeleteUser aplica el derecho al olvido: anonimiza la cuenta en lugar de borrarla,
+// de modo que las calificaciones y la auditoría se conservan
 type DeleteUser struct {
-	db domain.IUser
+	db       domain.IUser
+	erasure  domain.IUserErasure
+	sessions *SessionService
+	blobs    domain.IBlobStore
 }
 
-func NewDeleteUser(db domain.IUser) *DeleteUser {	
-	return &DeleteUser{db: db}
+func NewDeleteUser(db domain.IUser, erasure domain.IUserErasure, sessions *SessionService, blobs domain.IBlobStore) *DeleteUser {
+	return &DeleteUser{db: db, erasure: erasure, sessions: sessions, blobs: blobs}
 }
 
-func (du *DeleteUser) Execute(id int32) error {
-	return du.db.DeleteUser(id)
-}
\ No newline at end of file
+func (du *DeleteUser) Execute(actorID int32, clientIP string, id int32) error {
+	user, err := du.db.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// La contraseña "!" + aleatorio no tiene el formato de ningún hasher, así que nunca coincide
+	unusable, err := randomToken(16)
+	if err != nil {
+		return err
+	}
+
+	// Cuenta, comentarios y auditoría cambian juntos o no cambia nada
+	err = du.erasure.Erase(&domain.UserErasure{
+		UserID:        id,
+		UserName:      fmt.Sprintf("deleted-user-%d", id),
+		Email:         fmt.Sprintf("deleted-%d@deleted.invalid", id),
+		Password:      "!" + unusable,
+		CommentAuthor: DeletedUserPlaceholder,
+		Audit: &domain.AuditEntry{
+			ActorID:      actorID,
+			TargetUserID: id,
+			Action:       domain.AuditActionErasure,
+			IP:           clientIP,
+			CreatedAt:    time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("[Privacy] - Usuario ID: %d anonimizado por el usuario ID: %d", id, actorID)
+
+	// La transacción ya borró las sesiones; aquí solo se cierran las conexiones WebSocket abiertas.
+	// La cuenta ya está anonimizada, así que un fallo no debe convertirse en un error para el cliente
+	if err := du.sessions.RevokeAllForUser(id); err != nil {
+		log.Printf("[Privacy] - Error al cerrar las sesiones del usuario ID: %d: %v", id, err)
+	}
+	// La foto también es un dato personal; Erase ya limpió la referencia
+	if user.AvatarKey != "" {
+		deleteAvatar(du.blobs, user.AvatarKey)
+	}
+	return nil
+}
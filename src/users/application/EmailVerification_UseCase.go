@@ -0,0 +1,176 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var ErrInvalidVerificationToken = errors.New("el enlace de verificación es inválido o expiró")
+
+// ResendLimitError indica que se pidieron demasiados reenvíos de verificación
+type ResendLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResendLimitError) Error() string {
+	return fmt.Sprintf("demasiadas solicitudes de verificación, intenta de nuevo en %d segundos", int(e.RetryAfter.Seconds()))
+}
+
+// EmailVerificationService emite tokens de verificación y los envía por correo
+type EmailVerificationService struct {
+	tokens  domain.IEmailVerification
+	mailer  domain.Mailer
+	ttl     time.Duration
+	baseURL string
+}
+
+func NewEmailVerificationService(tokens domain.IEmailVerification, mailer domain.Mailer, ttl time.Duration, baseURL string) *EmailVerificationService {
+	return &EmailVerificationService{tokens: tokens, mailer: mailer, ttl: ttl, baseURL: baseURL}
+}
+
+// Send invalida los enlaces anteriores del usuario y envía uno nuevo
+func (vs *EmailVerificationService) Send(user *domain.User) error {
+	if err := vs.tokens.InvalidateForUser(user.ID); err != nil {
+		return err
+	}
+
+	raw, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	token := &domain.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: HashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(vs.ttl),
+	}
+	if err := vs.tokens.Save(token); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hola %s,\n\nConfirma tu cuenta abriendo el siguiente enlace (válido por %d horas):\n\n%s/api/v1/users/verify?token=%s\n",
+		user.UserName, int(vs.ttl.Hours()), strings.TrimRight(vs.baseURL, "/"), raw,
+	)
+	if err := vs.mailer.Send(user.Email, "Verifica tu cuenta", body); err != nil {
+		log.Printf("[Mailer] - Error al enviar el correo de verificación al usuario ID: %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+type VerifyEmail struct {
+	tokens domain.IEmailVerification
+}
+
+func NewVerifyEmail(tokens domain.IEmailVerification) *VerifyEmail {
+	return &VerifyEmail{tokens: tokens}
+}
+
+// Execute confirma el email del usuario asociado al token de un solo uso
+func (ve *VerifyEmail) Execute(rawToken string) error {
+	token, err := ve.tokens.GetByHash(HashToken(rawToken))
+	if err != nil {
+		return err
+	}
+	if token == nil || token.UsedAt != nil || token.IsExpired() {
+		return ErrInvalidVerificationToken
+	}
+
+	// El token se consume junto con la verificación: si una falla, la otra no se aplica
+	redeemed, err := ve.tokens.Redeem(token.ID, token.UserID)
+	if err != nil {
+		return err
+	}
+	if !redeemed {
+		return ErrInvalidVerificationToken
+	}
+	return nil
+}
+
+// ResendPolicy limita la frecuencia de reenvíos por email y por IP
+type ResendPolicy struct {
+	MinInterval     time.Duration
+	MaxPerHour      int
+	MaxPerHourPerIP int
+}
+
+type ResendVerification struct {
+	db           domain.IUser
+	attempts     domain.ILoginAttemptStore
+	verification *EmailVerificationService
+	policy       ResendPolicy
+}
+
+func NewResendVerification(db domain.IUser, attempts domain.ILoginAttemptStore, verification *EmailVerificationService, policy ResendPolicy) *ResendVerification {
+	return &ResendVerification{db: db, attempts: attempts, verification: verification, policy: policy}
+}
+
+// Execute reenvía el enlace si el email pertenece a una cuenta pendiente de verificación.
+// No revela si el email existe ni si ya está verificado: el límite se aplica antes de buscar
+// la cuenta y la respuesta es la misma en todos los casos.
+func (rv *ResendVerification) Execute(email string, ip string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ErrEmailRequired
+	}
+
+	if err := rv.throttle(email, ip); err != nil {
+		return err
+	}
+
+	user, err := rv.db.GetByEmail(email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified || !user.Estado {
+		return nil
+	}
+
+	return rv.verification.Send(user)
+}
+
+// throttle cuenta la solicitud por hash del email y por IP y devuelve un ResendLimitError si se superó algún límite
+func (rv *ResendVerification) throttle(email string, ip string) error {
+	now := time.Now()
+	emailKey := "resend:email:" + HashToken(strings.ToLower(email))
+	limits := map[string]int{emailKey: rv.policy.MaxPerHour}
+	if ip != "" {
+		limits["resend:ip:"+ip] = rv.policy.MaxPerHourPerIP
+	}
+
+	var retryAfter time.Duration
+	for key := range limits {
+		attempt, err := rv.attempts.Get(key)
+		if err != nil {
+			return err
+		}
+		if attempt.IsLocked(now) {
+			retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+		}
+		if key == emailKey && attempt != nil && now.Sub(attempt.LastFailureAt) < rv.policy.MinInterval {
+			retryAfter = max(retryAfter, rv.policy.MinInterval-now.Sub(attempt.LastFailureAt))
+		}
+	}
+	if retryAfter > 0 {
+		return &ResendLimitError{RetryAfter: retryAfter.Round(time.Second)}
+	}
+
+	for key, limit := range limits {
+		attempt, err := rv.attempts.RecordFailure(key, time.Hour)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && attempt.Failures >= limit {
+			if err := rv.attempts.Lock(key, now.Add(time.Hour)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"expresApi/src/users/domain"
+	"time"
+)
+
+// ExportUserData reúne en un solo documento todos los datos que se guardan de un usuario
+type ExportUserData struct {
+	db         domain.IUser
+	comments   domain.IUserComments
+	sessions   *SessionService
+	apiKeys    domain.IApiKey
+	twoFactor  domain.ITwoFactor
+	identities domain.IUserIdentity
+	audit      domain.IAuditLog
+}
+
+func NewExportUserData(db domain.IUser, comments domain.IUserComments, sessions *SessionService, apiKeys domain.IApiKey, twoFactor domain.ITwoFactor, identities domain.IUserIdentity, audit domain.IAuditLog) *ExportUserData {
+	return &ExportUserData{db: db, comments: comments, sessions: sessions, apiKeys: apiKeys, twoFactor: twoFactor, identities: identities, audit: audit}
+}
+
+func (ed *ExportUserData) Execute(actorID int32, clientIP string, id int32) (*domain.UserDataExport, error) {
+	user, err := ed.db.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se audita antes de reunir los datos para que la propia exportación aparezca en el archivo
+	if err := ed.audit.Record(&domain.AuditEntry{
+		ActorID:      actorID,
+		TargetUserID: id,
+		Action:       domain.AuditActionDataExport,
+		IP:           clientIP,
+		CreatedAt:    time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+
+	export := &domain.UserDataExport{GeneratedAt: time.Now().UTC(), Profile: user.Public()}
+
+	if export.Roles, err = ed.db.GetUserRoles(id); err != nil {
+		return nil, err
+	}
+	if export.Comments, err = ed.comments.ListByUserID(id); err != nil {
+		return nil, err
+	}
+	if export.Sessions, err = ed.sessions.History(id); err != nil {
+		return nil, err
+	}
+	if export.ApiKeys, err = ed.apiKeys.ListByUser(id); err != nil {
+		return nil, err
+	}
+	twoFactor, err := ed.twoFactor.GetByUserID(id)
+	if err != nil {
+		return nil, err
+	}
+	export.TwoFactor = twoFactor != nil && twoFactor.Enabled
+	if export.Identities, err = ed.identities.ListByUser(id); err != nil {
+		return nil, err
+	}
+	if export.AuditLog, err = ed.audit.ListByUser(id); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
@@ -0,0 +1,47 @@
+package application
+
+import "expresApi/src/users/domain"
+
+// Tamaño de los lotes leídos de la base de datos durante la exportación
+const exportBatchSize = 500
+
+type ExportUsers struct {
+	db domain.IUser
+}
+
+func NewExportUsers(db domain.IUser) *ExportUsers {
+	return &ExportUsers{db: db}
+}
+
+// Execute recorre todos los usuarios que cumplen los filtros en lotes y entrega cada lote a write,
+// de modo que la exportación no carga la tabla completa en memoria
+func (eu *ExportUsers) Execute(query domain.UserQuery, write func(users []domain.User) error) error {
+	if query.SortBy == "" {
+		query.SortBy = domain.UserSortID
+	}
+	if !userSortFields[query.SortBy] {
+		return ErrInvalidUserSort
+	}
+	query.Limit = exportBatchSize
+	query.Offset = 0
+	query.After = nil
+
+	for {
+		users, err := eu.db.FindUsers(query)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		if err := write(users); err != nil {
+			return err
+		}
+		if len(users) < exportBatchSize {
+			return nil
+		}
+
+		last := users[len(users)-1]
+		query.After = &domain.UserCursor{Value: last.SortValue(query.SortBy), ID: last.ID}
+	}
+}
@@ -0,0 +1,15 @@
+package application
+
+import "expresApi/src/users/domain"
+
+type GetUser struct {
+	db domain.IUser
+}
+
+func NewGetUser(db domain.IUser) *GetUser {
+	return &GetUser{db: db}
+}
+
+func (gu *GetUser) Execute(id int32) (*domain.User, error) {
+	return gu.db.GetByID(id)
+}
@@ -0,0 +1,119 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrImpersonateSelf    = errors.New("no puedes suplantarte a ti mismo")
+	ErrImpersonateAdmin   = errors.New("no se puede suplantar a otro administrador")
+	ErrImpersonateReason  = errors.New("el motivo de la suplantación es obligatorio")
+	ErrImpersonateSession = errors.New("la suplantación requiere una sesión iniciada")
+)
+
+// ImpersonationResult es el access token con el que el administrador actúa como el usuario.
+// No incluye refresh token: al expirar hay que volver a solicitarlo.
+type ImpersonationResult struct {
+	User           *domain.User
+	ImpersonatorID int32
+	AccessToken    string
+	ExpiresAt      time.Time
+}
+
+// ImpersonateUser emite un token de corta duración para que un administrador actúe como otro usuario.
+// El token queda ligado a la sesión del administrador: al cerrarla deja de valer.
+type ImpersonateUser struct {
+	db     domain.IUser
+	tokens *TokenService
+	audit  domain.IAuditLog
+	ttl    time.Duration
+}
+
+func NewImpersonateUser(db domain.IUser, tokens *TokenService, audit domain.IAuditLog, ttl time.Duration) *ImpersonateUser {
+	return &ImpersonateUser{db: db, tokens: tokens, audit: audit, ttl: ttl}
+}
+
+func (iu *ImpersonateUser) Execute(actorID int32, sessionID string, clientIP string, targetID int32, reason string) (*ImpersonationResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrImpersonateReason
+	}
+	if sessionID == "" {
+		return nil, ErrImpersonateSession
+	}
+	if actorID == targetID {
+		return nil, ErrImpersonateSelf
+	}
+
+	target, err := iu.db.GetByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !target.Estado {
+		return nil, ErrAccountDeactivated
+	}
+
+	roles, err := iu.db.GetUserRoles(targetID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Name == domain.RoleAdmin {
+			return nil, ErrImpersonateAdmin
+		}
+	}
+
+	token, expiresAt, err := iu.tokens.GenerateImpersonation(target, actorID, sessionID, iu.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := iu.audit.Record(&domain.AuditEntry{
+		ActorID:      actorID,
+		TargetUserID: targetID,
+		Action:       domain.AuditActionImpersonationStart,
+		Details:      fmt.Sprintf("motivo: %s; expira: %s", reason, expiresAt.UTC().Format(time.RFC3339)),
+		IP:           clientIP,
+		CreatedAt:    time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+	log.Printf("[Impersonation] - El usuario ID: %d suplanta al usuario ID: %d hasta %s", actorID, targetID, expiresAt.UTC().Format(time.RFC3339))
+
+	return &ImpersonationResult{
+		User:           target,
+		ImpersonatorID: actorID,
+		AccessToken:    token,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+// ImpersonationAudit registra en la bitácora cada petición hecha con un token de suplantación
+type ImpersonationAudit struct {
+	audit domain.IAuditLog
+}
+
+func NewImpersonationAudit(audit domain.IAuditLog) *ImpersonationAudit {
+	return &ImpersonationAudit{audit: audit}
+}
+
+// Record anota la petición; un fallo de la bitácora no invalida la petición ya atendida
+func (ia *ImpersonationAudit) Record(impersonatorID int32, targetID int32, method string, path string, status int, clientIP string) {
+	log.Printf("[Impersonation] - Usuario ID: %d como usuario ID: %d: %s %s -> %d", impersonatorID, targetID, method, path, status)
+	err := ia.audit.Record(&domain.AuditEntry{
+		ActorID:      impersonatorID,
+		TargetUserID: targetID,
+		Action:       domain.AuditActionImpersonationRequest,
+		Details:      fmt.Sprintf("%s %s -> %d", method, path, status),
+		IP:           clientIP,
+		CreatedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("[Impersonation] - Error al registrar la petición en la auditoría: %v", err)
+	}
+}
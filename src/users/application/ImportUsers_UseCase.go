@@ -0,0 +1,233 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"log"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// MaxImportRows limita el tamaño de una importación para no bloquear el servidor
+const MaxImportRows = 5000
+
+// Motivo que queda registrado en las cuentas importadas como inactivas
+const ImportDeactivationReason = "importada como inactiva"
+
+var ErrImportTooLarge = errors.New("la importación supera el máximo de filas permitido")
+
+// Estados posibles de una fila importada
+const (
+	ImportRowValid   = "valid"
+	ImportRowCreated = "created"
+	ImportRowError   = "error"
+)
+
+// ImportUserRow es una fila del CSV tal como llegó; role y estado son opcionales
+type ImportUserRow struct {
+	Line     int
+	UserName string
+	Email    string
+	Role     string
+	Estado   string
+}
+
+type ImportRowResult struct {
+	Line             int      `json:"line"`
+	UserName         string   `json:"username"`
+	Email            string   `json:"email"`
+	Role             string   `json:"role"`
+	Estado           bool     `json:"estado"`
+	Status           string   `json:"status"`
+	Errors           []string `json:"errors,omitempty"`
+	UserID           int32    `json:"user_id,omitempty"`
+	PasswordLinkSent bool     `json:"password_link_sent,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+type ImportUsers struct {
+	db           domain.IUser
+	verification *EmailVerificationService
+	resets       *RequestPasswordReset
+}
+
+func NewImportUsers(db domain.IUser, verification *EmailVerificationService, resets *RequestPasswordReset) *ImportUsers {
+	return &ImportUsers{db: db, verification: verification, resets: resets}
+}
+
+// Execute valida todas las filas y, si no es dry run, crea las válidas sin contraseña utilizable;
+// las cuentas activas reciben por correo el enlace para elegirla.
+// Las filas son independientes: un error en una no impide crear las demás.
+// Asignar un rol distinto de customer requiere que el actor pueda gestionar roles.
+func (iu *ImportUsers) Execute(actorID int32, canManageRoles bool, rows []ImportUserRow, dryRun bool) (*ImportReport, error) {
+	if len(rows) > MaxImportRows {
+		return nil, ErrImportTooLarge
+	}
+
+	roles, err := iu.db.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	knownRoles := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		knownRoles[role.Name] = true
+	}
+
+	report := &ImportReport{DryRun: dryRun, Total: len(rows), Rows: make([]ImportRowResult, 0, len(rows))}
+	seenUserNames := map[string]int{}
+	seenEmails := map[string]int{}
+
+	for _, row := range rows {
+		result := iu.validate(row, knownRoles, canManageRoles, seenUserNames, seenEmails)
+		if len(result.Errors) == 0 && !dryRun {
+			iu.create(actorID, &result)
+		}
+
+		switch {
+		case len(result.Errors) > 0:
+			result.Status = ImportRowError
+			report.Failed++
+		case dryRun:
+			result.Status = ImportRowValid
+			report.Valid++
+		default:
+			result.Status = ImportRowCreated
+			report.Valid++
+			report.Created++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if !dryRun {
+		log.Printf("[Import] - Importación de usuarios por el usuario ID: %d: %d creados, %d con errores", actorID, report.Created, report.Failed)
+	}
+	return report, nil
+}
+
+func (iu *ImportUsers) validate(row ImportUserRow, knownRoles map[string]bool, canManageRoles bool, seenUserNames map[string]int, seenEmails map[string]int) ImportRowResult {
+	result := ImportRowResult{
+		Line:     row.Line,
+		UserName: strings.TrimSpace(row.UserName),
+		Email:    strings.TrimSpace(row.Email),
+		Role:     strings.ToLower(strings.TrimSpace(row.Role)),
+		Estado:   true,
+	}
+	addError := func(message string) {
+		result.Errors = append(result.Errors, message)
+	}
+
+	if result.UserName == "" {
+		addError(ErrUserNameRequired.Error())
+	}
+	if result.Email == "" {
+		addError(ErrEmailRequired.Error())
+	} else if address, err := mail.ParseAddress(result.Email); err != nil || address.Address != result.Email {
+		addError("el email no es válido")
+	}
+
+	if result.Role == "" {
+		result.Role = domain.RoleCustomer
+	}
+	if !knownRoles[result.Role] {
+		addError(domain.ErrRoleNotFound.Error() + ": " + result.Role)
+	} else if result.Role != domain.RoleCustomer && !canManageRoles {
+		addError("se requiere el permiso " + domain.PermissionRolesManage + " para asignar el rol " + result.Role)
+	}
+
+	if estado, ok := parseImportEstado(row.Estado); ok {
+		result.Estado = estado
+	} else {
+		addError("estado inválido, usa true/false o activo/inactivo")
+	}
+
+	// Duplicados dentro del propio archivo
+	if result.UserName != "" {
+		key := strings.ToLower(result.UserName)
+		if line, ok := seenUserNames[key]; ok {
+			addError("username repetido en la línea " + strconv.Itoa(line))
+		} else {
+			seenUserNames[key] = row.Line
+		}
+	}
+	if result.Email != "" {
+		key := strings.ToLower(result.Email)
+		if line, ok := seenEmails[key]; ok {
+			addError("email repetido en la línea " + strconv.Itoa(line))
+		} else {
+			seenEmails[key] = row.Line
+		}
+	}
+
+	// Duplicados contra los usuarios existentes
+	if result.UserName != "" && result.Email != "" {
+		if err := ensureUnique(iu.db, 0, &result.UserName, nil); err != nil {
+			addError(err.Error())
+		}
+		if err := ensureUnique(iu.db, 0, nil, &result.Email); err != nil {
+			addError(err.Error())
+		}
+	}
+	return result
+}
+
+// create guarda el usuario y registra cualquier error en la fila. La contraseña "!" + aleatorio
+// no coincide con ninguna, así que la cuenta solo se usa tras elegirla con el enlace enviado.
+func (iu *ImportUsers) create(actorID int32, result *ImportRowResult) {
+	unusable, err := randomToken(16)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return
+	}
+
+	var deactivation *domain.UserDeactivation
+	if !result.Estado {
+		deactivation = &domain.UserDeactivation{Reason: ImportDeactivationReason, ActorID: actorID}
+	}
+
+	id, err := iu.db.SaveUser(result.UserName, result.Email, "!"+unusable, result.Role, deactivation)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return
+	}
+	result.UserID = id
+
+	user, err := iu.db.GetByID(id)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return
+	}
+	if err := iu.verification.Send(user); err != nil {
+		log.Printf("[Import] - No se pudo enviar la verificación al usuario ID: %d: %v", user.ID, err)
+	}
+
+	// Igual que el restablecimiento, las cuentas inactivas no reciben enlace; al activarlas
+	// el usuario puede pedirlo con la opción de contraseña olvidada
+	if !user.Estado {
+		return
+	}
+	if err := iu.resets.SendSetPasswordLink(user); err != nil {
+		log.Printf("[Import] - No se pudo enviar el enlace de contraseña al usuario ID: %d: %v", user.ID, err)
+		return
+	}
+	result.PasswordLinkSent = true
+}
+
+func parseImportEstado(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "true", "1", "activo":
+		return true, true
+	case "false", "0", "inactivo":
+		return false, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,17 @@
+package application
+
+import (
+	"expresApi/src/users/domain"
+)
+
+type ListRoles struct {
+	db domain.IUser
+}
+
+func NewListRoles(db domain.IUser) *ListRoles {
+	return &ListRoles{db: db}
+}
+
+func (lr *ListRoles) Execute() ([]domain.Role, error) {
+	return lr.db.ListRoles()
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// LockoutError indica que el login está bloqueado temporalmente
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("demasiados intentos fallidos, intenta de nuevo en %d segundos", int(e.RetryAfter.Seconds()))
+}
+
+const CodeTooManyAttempts = "TOO_MANY_ATTEMPTS"
+
+// LoginThrottlePolicy configura los umbrales y la espera exponencial
+type LoginThrottlePolicy struct {
+	MaxUserFailures int
+	MaxIPFailures   int
+	FailureWindow   time.Duration
+	BaseLockout     time.Duration
+	MaxLockout      time.Duration
+}
+
+// LoginThrottle lleva la cuenta de fallos por usuario y por IP y aplica bloqueos crecientes
+type LoginThrottle struct {
+	store  domain.ILoginAttemptStore
+	policy LoginThrottlePolicy
+}
+
+func NewLoginThrottle(store domain.ILoginAttemptStore, policy LoginThrottlePolicy) *LoginThrottle {
+	return &LoginThrottle{store: store, policy: policy}
+}
+
+func UserAttemptKey(userName string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(userName))
+}
+
+func IPAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check devuelve un LockoutError si el usuario o la IP están bloqueados
+func (lt *LoginThrottle) Check(userName string, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, key := range lt.keys(userName, ip) {
+		attempt, err := lt.store.Get(key)
+		if err != nil {
+			return err
+		}
+		if attempt.IsLocked(now) {
+			if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: time.Duration(math.Ceil(retryAfter.Seconds())) * time.Second}
+	}
+	return nil
+}
+
+// Failure registra un intento fallido y bloquea la clave al alcanzar el umbral
+func (lt *LoginThrottle) Failure(userName string, ip string) error {
+	for _, key := range lt.keys(userName, ip) {
+		limit := lt.policy.MaxUserFailures
+		if strings.HasPrefix(key, "ip:") {
+			limit = lt.policy.MaxIPFailures
+		}
+
+		attempt, err := lt.store.RecordFailure(key, lt.policy.FailureWindow)
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || attempt.Failures < limit {
+			continue
+		}
+
+		lockout := lt.lockoutFor(attempt.LockCount)
+		if err := lt.store.Lock(key, time.Now().Add(lockout)); err != nil {
+			return err
+		}
+		log.Printf("[Auth] - Login bloqueado para %s durante %s tras %d intentos fallidos (bloqueo #%d)", key, lockout, attempt.Failures, attempt.LockCount+1)
+	}
+
+	return nil
+}
+
+// Success reinicia solo el contador del usuario; los fallos de la IP caducan con el tiempo,
+// porque un login correcto con otra cuenta no demuestra nada sobre los intentos anteriores
+func (lt *LoginThrottle) Success(userName string, ip string) error {
+	if strings.TrimSpace(userName) == "" {
+		return nil
+	}
+	return lt.store.Reset(UserAttemptKey(userName))
+}
+
+// Clear elimina el bloqueo de un usuario y/o una IP (uso administrativo)
+func (lt *LoginThrottle) Clear(userName string, ip string) error {
+	for _, key := range lt.keys(userName, ip) {
+		if err := lt.store.Reset(key); err != nil {
+			return err
+		}
+		log.Printf("[Auth] - Bloqueo de login eliminado manualmente para %s", key)
+	}
+	return nil
+}
+
+// lockoutFor duplica la espera por cada bloqueo previo hasta MaxLockout
+func (lt *LoginThrottle) lockoutFor(previousLocks int) time.Duration {
+	lockout := lt.policy.BaseLockout
+	for i := 0; i < previousLocks && lockout < lt.policy.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lt.policy.MaxLockout > 0 && lockout > lt.policy.MaxLockout {
+		lockout = lt.policy.MaxLockout
+	}
+	return lockout
+}
+
+func (lt *LoginThrottle) keys(userName string, ip string) []string {
+	keys := []string{}
+	if strings.TrimSpace(userName) != "" {
+		keys = append(keys, UserAttemptKey(userName))
+	}
+	if ip != "" {
+		keys = append(keys, IPAttemptKey(ip))
+	}
+	return keys
+}
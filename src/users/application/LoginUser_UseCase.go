@@ -1,34 +1,128 @@
 package application
 
 import (
-	"expresApi/src/users/domain"
 	"errors"
-
-	"golang.org/x/crypto/bcrypt"
+	"expresApi/src/users/domain"
+	"log"
+	"time"
 )
 
 type LoginUser struct {
-	db domain.IUser
+	db           domain.IUser
+	issuer       *SessionIssuer
+	throttle     *LoginThrottle
+	twoFactor    domain.ITwoFactor
+	tokens       *TokenService
+	hashing      *PasswordHashing
+	challengeTTL time.Duration
 }
 
-func NewLoginUser(db domain.IUser) *LoginUser {
+func NewLoginUser(db domain.IUser, issuer *SessionIssuer, throttle *LoginThrottle, twoFactor domain.ITwoFactor, tokens *TokenService, hashing *PasswordHashing, challengeTTL time.Duration) *LoginUser {
 	return &LoginUser{
-		db: db,
+		db:           db,
+		issuer:       issuer,
+		throttle:     throttle,
+		twoFactor:    twoFactor,
+		tokens:       tokens,
+		hashing:      hashing,
+		challengeTTL: challengeTTL,
 	}
 }
 
-func (lu *LoginUser) Execute(userName string, password string) (*domain.User, error) {
+func (lu *LoginUser) Execute(userName string, password string, client ClientInfo) (*LoginResult, error) {
+	// Rechazar de inmediato si el usuario o la IP están bloqueados
+	if err := lu.throttle.Check(userName, client.IP); err != nil {
+		return nil, err
+	}
+
 	// Obtener usuario solo con el username
 	user, err := lu.db.GetUserByCredentials(userName)
 	if err != nil {
-		return nil, err
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, lu.failure(userName, client.IP)
 	}
 
 	// Verificar la contraseña
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	ok, rehash := lu.hashing.Verify(user.Password, password)
+	if !ok {
+		return nil, lu.failure(userName, client.IP)
+	}
+	if rehash {
+		lu.rehash(user.ID, password)
+	}
+
+	// Con 2FA activo se devuelve un desafío; los contadores se reinician al completar el segundo paso
+	if challenge, err := lu.authorize(user); err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	if err := lu.throttle.Success(userName, client.IP); err != nil {
+		return nil, err
+	}
+
+	return lu.issuer.Issue(user, client)
+}
+
+// CompleteExternalLogin inicia sesión con un usuario ya autenticado por un proveedor externo;
+// se aplican las mismas comprobaciones que tras validar la contraseña, incluido el 2FA
+func (lu *LoginUser) CompleteExternalLogin(user *domain.User, client ClientInfo) (*LoginResult, error) {
+	if challenge, err := lu.authorize(user); err != nil || challenge != nil {
+		return challenge, err
+	}
+	return lu.issuer.Issue(user, client)
+}
+
+// authorize comprueba el estado de la cuenta una vez verificadas las credenciales.
+// Devuelve un desafío de 2FA si el usuario lo tiene activo, o nil si ya se puede emitir la sesión.
+func (lu *LoginUser) authorize(user *domain.User) (*LoginResult, error) {
+	// Rechazar cuentas desactivadas (después de validar la contraseña para no revelar su estado)
+	if !user.Estado {
+		return nil, ErrAccountDeactivated
+	}
+	if !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	twoFactor, err := lu.twoFactor.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return nil, nil
+	}
+
+	challenge, expiresAt, err := lu.tokens.GenerateChallenge(user, lu.challengeTTL)
 	if err != nil {
-		return nil, errors.New("credenciales inválidas")
+		return nil, err
 	}
+	return &LoginResult{
+		User:               user,
+		TwoFactorRequired:  true,
+		Challenge:          challenge,
+		ChallengeExpiresAt: expiresAt,
+	}, nil
+}
 
-	return user, nil
+// failure registra el intento fallido y devuelve el error de credenciales
+func (lu *LoginUser) failure(userName string, clientIP string) error {
+	if err := lu.throttle.Failure(userName, clientIP); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// rehash regenera el hash con el algoritmo y los parámetros actuales aprovechando que se conoce
+// la contraseña en claro; si falla se conserva el hash anterior, que sigue siendo válido
+func (lu *LoginUser) rehash(userID int32, password string) {
+	hashed, err := lu.hashing.Hash(password)
+	if err == nil {
+		err = lu.db.UpdateUser(userID, domain.UserChanges{Password: &hashed})
+	}
+	if err != nil {
+		log.Printf("[Auth] - Error al actualizar el hash de la contraseña del usuario ID: %d: %v", userID, err)
+		return
+	}
+	log.Printf("[Auth] - Hash de la contraseña actualizado para el usuario ID: %d", userID)
 }
@@ -0,0 +1,14 @@
+package application
+
+type LogoutUser struct {
+	sessions *SessionService
+}
+
+func NewLogoutUser(sessions *SessionService) *LogoutUser {
+	return &LogoutUser{sessions: sessions}
+}
+
+// Execute cierra todas las sesiones del usuario y revoca todos sus refresh tokens
+func (lu *LogoutUser) Execute(userID int32) error {
+	return lu.sessions.RevokeAllForUser(userID)
+}
@@ -0,0 +1,360 @@
+package application
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOIDCInvalidState      = &AuthError{Code: "OIDC_INVALID_STATE", Message: "la solicitud de inicio de sesión es inválida o expiró"}
+	ErrOIDCInvalidToken      = &AuthError{Code: "OIDC_INVALID_TOKEN", Message: "el proveedor devolvió una identidad inválida"}
+	ErrOIDCEmailNotVerified  = &AuthError{Code: "OIDC_EMAIL_NOT_VERIFIED", Message: "el proveedor no confirma que el email esté verificado"}
+	ErrOIDCAccountNotFound   = &AuthError{Code: "OIDC_ACCOUNT_NOT_FOUND", Message: "no hay una cuenta vinculada a esta identidad"}
+	ErrOIDCEmailConflict     = &AuthError{Code: "OIDC_EMAIL_CONFLICT", Message: "ya existe una cuenta con ese email; inicia sesión y vincula la identidad desde tu perfil"}
+	ErrOIDCIdentityLinked    = errors.New("la identidad ya está vinculada a otra cuenta")
+	ErrOIDCLastSignInMethod  = errors.New("no puedes desvincular tu única forma de iniciar sesión; define antes una contraseña")
+	ErrOIDCProviderDisabled  = errors.New("el inicio de sesión con un proveedor externo no está configurado")
+	errOIDCUserNameExhausted = errors.New("no se pudo generar un nombre de usuario libre")
+)
+
+// StartOIDCLogin prepara la redirección al proveedor con state, nonce y PKCE
+type StartOIDCLogin struct {
+	identities domain.IUserIdentity
+	provider   domain.IIdentityProvider
+	ttl        time.Duration
+}
+
+func NewStartOIDCLogin(identities domain.IUserIdentity, provider domain.IIdentityProvider, ttl time.Duration) *StartOIDCLogin {
+	return &StartOIDCLogin{identities: identities, provider: provider, ttl: ttl}
+}
+
+// TTL es cuánto tiempo puede tardar el usuario en volver del proveedor
+func (so *StartOIDCLogin) TTL() time.Duration {
+	return so.ttl
+}
+
+// Execute devuelve la URL de autorización del proveedor y el vínculo del state con el navegador,
+// que el controlador guarda en una cookie y CompleteOIDCLogin exige de vuelta.
+// linkUserID es el usuario autenticado que vincula una identidad nueva, o 0 para iniciar sesión.
+func (so *StartOIDCLogin) Execute(linkUserID int32) (string, string, error) {
+	if so.provider == nil {
+		return "", "", ErrOIDCProviderDisabled
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	// El verificador se queda en el servidor; al proveedor solo viaja su SHA-256
+	verifier, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := so.identities.SaveAuthRequest(&domain.OIDCAuthRequest{
+		StateHash:    HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().UTC().Add(so.ttl),
+	}); err != nil {
+		return "", "", err
+	}
+
+	authorizationURL, err := so.provider.AuthorizationURL(state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+	return authorizationURL, OIDCStateBinding(state), nil
+}
+
+// OIDCStateBinding es el valor que ata el state al navegador que inició el flujo. Sin él, quien
+// inicia un flujo puede enviar su URL a otra persona y la vuelta del proveedor se procesaría con
+// la identidad de esa persona (vinculándola a la cuenta del atacante o iniciando su sesión).
+func OIDCStateBinding(state string) string {
+	return HashToken("oidc-state:" + state)
+}
+
+// pkceChallenge calcula el code_challenge S256 del verificador
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// OIDCCallbackResult es el resultado de la vuelta del proveedor: una identidad vinculada
+// a la cuenta del usuario autenticado o un inicio de sesión
+type OIDCCallbackResult struct {
+	Identity *domain.UserIdentity
+	Linked   bool
+	Login    *LoginResult
+}
+
+// CompleteOIDCLogin valida la respuesta del proveedor y resuelve el usuario local:
+// por identidad ya vinculada, por email verificado o creando una cuenta nueva
+type CompleteOIDCLogin struct {
+	db            domain.IUser
+	identities    domain.IUserIdentity
+	provider      domain.IIdentityProvider
+	login         *LoginUser
+	autoProvision bool
+}
+
+func NewCompleteOIDCLogin(db domain.IUser, identities domain.IUserIdentity, provider domain.IIdentityProvider, login *LoginUser, autoProvision bool) *CompleteOIDCLogin {
+	return &CompleteOIDCLogin{db: db, identities: identities, provider: provider, login: login, autoProvision: autoProvision}
+}
+
+// Execute completa el flujo; binding es el valor de OIDCStateBinding que guardó el navegador al iniciarlo
+func (co *CompleteOIDCLogin) Execute(state string, binding string, code string, client ClientInfo) (*OIDCCallbackResult, error) {
+	if co.provider == nil {
+		return nil, ErrOIDCProviderDisabled
+	}
+	if state == "" || code == "" {
+		return nil, ErrOIDCInvalidState
+	}
+	if subtle.ConstantTimeCompare([]byte(OIDCStateBinding(state)), []byte(binding)) != 1 {
+		return nil, ErrOIDCInvalidState
+	}
+
+	// La solicitud se consume antes de canjear el código para que un state no sirva dos veces
+	request, err := co.identities.ConsumeAuthRequest(HashToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.IsExpired() {
+		return nil, ErrOIDCInvalidState
+	}
+
+	external, err := co.provider.Exchange(code, request.CodeVerifier)
+	if err != nil {
+		log.Printf("[OIDC] - Error al canjear el código con %s: %v", co.provider.Issuer(), err)
+		return nil, ErrOIDCInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(external.Nonce), []byte(request.Nonce)) != 1 {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	existing, err := co.identities.GetBySubject(external.Issuer, external.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.LinkUserID != 0 {
+		return co.link(request.LinkUserID, external, existing)
+	}
+
+	result := &OIDCCallbackResult{}
+	var user *domain.User
+	if existing != nil {
+		if user, err = co.db.GetByID(existing.UserID); err != nil {
+			return nil, err
+		}
+		result.Identity = existing
+	} else {
+		if user, err = co.resolveUser(external); err != nil {
+			return nil, err
+		}
+		if result.Identity, err = co.save(user.ID, external); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := co.identities.TouchLastLogin(result.Identity.ID); err != nil {
+		return nil, err
+	}
+	if result.Login, err = co.login.CompleteExternalLogin(user, client); err != nil {
+		return nil, err
+	}
+	log.Printf("[OIDC] - Inicio de sesión del usuario ID: %d con %s", user.ID, external.Issuer)
+	return result, nil
+}
+
+// link vincula la identidad a la cuenta del usuario que inició el flujo desde su perfil
+func (co *CompleteOIDCLogin) link(userID int32, external *domain.ExternalIdentity, existing *domain.UserIdentity) (*OIDCCallbackResult, error) {
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, ErrOIDCIdentityLinked
+		}
+		return &OIDCCallbackResult{Identity: existing, Linked: true}, nil
+	}
+
+	if _, err := co.db.GetByID(userID); err != nil {
+		return nil, err
+	}
+	identity, err := co.save(userID, external)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[OIDC] - Identidad de %s vinculada al usuario ID: %d", external.Issuer, userID)
+	return &OIDCCallbackResult{Identity: identity, Linked: true}, nil
+}
+
+// resolveUser busca la cuenta por email verificado o la crea si el aprovisionamiento está activo
+func (co *CompleteOIDCLogin) resolveUser(external *domain.ExternalIdentity) (*domain.User, error) {
+	if external.Email == "" || !external.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := co.db.GetByEmail(external.Email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+	if user != nil {
+		// Solo se vincula automáticamente si la cuenta local también demostró ser dueña del email;
+		// si no, alguien podría registrar el email antes que su dueño y quedarse con la cuenta
+		if !user.EmailVerified {
+			return nil, ErrOIDCEmailConflict
+		}
+		return user, nil
+	}
+
+	if !co.autoProvision {
+		return nil, ErrOIDCAccountNotFound
+	}
+	return co.provision(external)
+}
+
+// provision crea una cuenta sin contraseña utilizable con el email ya verificado por el proveedor
+func (co *CompleteOIDCLogin) provision(external *domain.ExternalIdentity) (*domain.User, error) {
+	userName, err := co.availableUserName(external)
+	if err != nil {
+		return nil, err
+	}
+
+	// Igual que en las cuentas anonimizadas, "!" + aleatorio no coincide con ninguna contraseña;
+	// el usuario puede definir una con el restablecimiento de contraseña
+	unusable, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	id, err := co.db.SaveUser(userName, external.Email, "!"+unusable, domain.RoleCustomer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := co.db.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := co.db.MarkEmailVerified(user.ID); err != nil {
+		return nil, err
+	}
+	user.EmailVerified = true
+
+	log.Printf("[OIDC] - Usuario ID: %d creado a partir de una identidad de %s", user.ID, external.Issuer)
+	return user, nil
+}
+
+var userNameUnsafe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+const (
+	maxProvisionedUserNameLength = 40
+	maxUserNameAttempts          = 20
+)
+
+// availableUserName deriva un nombre de usuario del preferred_username o del email,
+// añadiendo un sufijo numérico si ya está en uso
+func (co *CompleteOIDCLogin) availableUserName(external *domain.ExternalIdentity) (string, error) {
+	base := external.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(external.Email, "@")
+	}
+	base = strings.Trim(userNameUnsafe.ReplaceAllString(strings.ToLower(base), "-"), "-.")
+	if len(base) > maxProvisionedUserNameLength {
+		base = base[:maxProvisionedUserNameLength]
+	}
+	if base == "" {
+		base = "usuario"
+	}
+
+	candidate := base
+	for attempt := 1; attempt <= maxUserNameAttempts; attempt++ {
+		_, err := co.db.GetUserByCredentials(candidate)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%d", base, attempt+1)
+	}
+	return "", errOIDCUserNameExhausted
+}
+
+func (co *CompleteOIDCLogin) save(userID int32, external *domain.ExternalIdentity) (*domain.UserIdentity, error) {
+	identity := &domain.UserIdentity{
+		UserID:    userID,
+		Issuer:    external.Issuer,
+		Subject:   external.Subject,
+		Email:     external.Email,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := co.identities.Save(identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+type ListIdentities struct {
+	identities domain.IUserIdentity
+}
+
+func NewListIdentities(identities domain.IUserIdentity) *ListIdentities {
+	return &ListIdentities{identities: identities}
+}
+
+func (li *ListIdentities) Execute(userID int32) ([]domain.UserIdentity, error) {
+	return li.identities.ListByUser(userID)
+}
+
+type UnlinkIdentity struct {
+	db         domain.IUser
+	identities domain.IUserIdentity
+	hashing    *PasswordHashing
+}
+
+func NewUnlinkIdentity(db domain.IUser, identities domain.IUserIdentity, hashing *PasswordHashing) *UnlinkIdentity {
+	return &UnlinkIdentity{db: db, identities: identities, hashing: hashing}
+}
+
+// Execute desvincula la identidad si al usuario le queda otra forma de iniciar sesión
+func (ui *UnlinkIdentity) Execute(userID int32, id int64) error {
+	user, err := ui.db.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	identities, err := ui.identities.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, identity := range identities {
+		found = found || identity.ID == id
+	}
+	if !found {
+		return domain.ErrIdentityNotFound
+	}
+	if len(identities) == 1 && !ui.hashing.Recognizes(user.Password) {
+		return ErrOIDCLastSignInMethod
+	}
+
+	deleted, err := ui.identities.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return domain.ErrIdentityNotFound
+	}
+	return nil
+}
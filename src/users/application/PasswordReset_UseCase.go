@@ -0,0 +1,153 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var ErrInvalidResetToken = errors.New("el enlace de restablecimiento es inválido o expiró")
+
+type RequestPasswordReset struct {
+	db      domain.IUser
+	resets  domain.IPasswordReset
+	mailer  domain.Mailer
+	ttl     time.Duration
+	baseURL string
+}
+
+func NewRequestPasswordReset(db domain.IUser, resets domain.IPasswordReset, mailer domain.Mailer, ttl time.Duration, baseURL string) *RequestPasswordReset {
+	return &RequestPasswordReset{db: db, resets: resets, mailer: mailer, ttl: ttl, baseURL: baseURL}
+}
+
+// Execute envía un enlace de restablecimiento si el email pertenece a una cuenta activa.
+// Nunca indica si el email existe: solo devuelve errores internos.
+func (rp *RequestPasswordReset) Execute(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ErrEmailRequired
+	}
+
+	user, err := rp.db.GetByEmail(email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Estado {
+		return nil
+	}
+
+	raw, err := rp.issue(user.ID)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hola %s,\n\nPara restablecer tu contraseña abre el siguiente enlace (válido por %d minutos):\n\n%s\n\nSi no solicitaste el cambio, ignora este mensaje.\n",
+		user.UserName, int(rp.ttl.Minutes()), rp.link(raw),
+	)
+	if err := rp.mailer.Send(user.Email, "Restablecer contraseña", body); err != nil {
+		// No se expone el fallo al cliente para no revelar que el email existe
+		log.Printf("[Mailer] - Error al enviar el correo de restablecimiento al usuario ID: %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// SendSetPasswordLink envía a una cuenta recién creada por un administrador el enlace para elegir
+// su contraseña, de modo que ninguna credencial pase por la respuesta de la API
+func (rp *RequestPasswordReset) SendSetPasswordLink(user *domain.User) error {
+	raw, err := rp.issue(user.ID)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hola %s,\n\nSe creó una cuenta para ti. Para elegir tu contraseña abre el siguiente enlace (válido por %d minutos):\n\n%s\n\nSi el enlace expira, solicita uno nuevo con la opción de contraseña olvidada.\n",
+		user.UserName, int(rp.ttl.Minutes()), rp.link(raw),
+	)
+	return rp.mailer.Send(user.Email, "Elige tu contraseña", body)
+}
+
+// issue guarda un token nuevo para el usuario y devuelve su valor en claro; solo el último enlace es válido
+func (rp *RequestPasswordReset) issue(userID int32) (string, error) {
+	if err := rp.resets.InvalidateForUser(userID); err != nil {
+		return "", err
+	}
+
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	token := &domain.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: HashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(rp.ttl),
+	}
+	if err := rp.resets.Save(token); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (rp *RequestPasswordReset) link(raw string) string {
+	return strings.TrimRight(rp.baseURL, "/") + "/reset-password?token=" + raw
+}
+
+type ResetPassword struct {
+	db       domain.IUser
+	resets   domain.IPasswordReset
+	sessions *SessionService
+	policy   *PasswordPolicy
+	hashing  *PasswordHashing
+}
+
+func NewResetPassword(db domain.IUser, resets domain.IPasswordReset, sessions *SessionService, policy *PasswordPolicy, hashing *PasswordHashing) *ResetPassword {
+	return &ResetPassword{db: db, resets: resets, sessions: sessions, policy: policy, hashing: hashing}
+}
+
+// Execute valida el token de un solo uso y guarda la nueva contraseña hasheada
+func (rp *ResetPassword) Execute(rawToken string, newPassword string) error {
+	if newPassword == "" {
+		return ErrPasswordRequired
+	}
+
+	token, err := rp.resets.GetByHash(HashToken(rawToken))
+	if err != nil {
+		return err
+	}
+	if token == nil || token.UsedAt != nil || token.IsExpired() {
+		return ErrInvalidResetToken
+	}
+
+	// La política se valida antes de consumir el token para que el usuario pueda reintentar
+	user, err := rp.db.GetByID(token.UserID)
+	if err != nil {
+		return err
+	}
+	if err := rp.policy.Validate("newPassword", newPassword, user.UserName, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := rp.hashing.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// El token se consume en la misma transacción que el cambio de contraseña
+	used, err := rp.resets.Redeem(token.ID, token.UserID, hashedPassword)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	log.Printf("[Auth] - Contraseña restablecida para el usuario ID: %d", token.UserID)
+	return rp.sessions.RevokeAllForUser(token.UserID)
+}
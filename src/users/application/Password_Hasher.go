@@ -0,0 +1,191 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUnknownHashFormat = errors.New("formato de hash de contraseña desconocido")
+
+// PasswordHasher genera y verifica hashes autodescriptivos: el propio hash indica
+// el algoritmo y los parámetros con los que se generó
+type PasswordHasher interface {
+	// Hash genera el hash de la contraseña con los parámetros actuales
+	Hash(password string) (string, error)
+	// Recognizes indica si el hash tiene el formato de este algoritmo
+	Recognizes(hash string) bool
+	// Verify compara la contraseña con un hash reconocido por este algoritmo; un hash corrupto nunca coincide
+	Verify(hash string, password string) bool
+	// NeedsRehash indica si el hash se generó con parámetros distintos a los actuales
+	NeedsRehash(hash string) bool
+}
+
+// BcryptHasher genera hashes $2a$ con el costo configurado
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (h *BcryptHasher) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h *BcryptHasher) Verify(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher genera hashes en formato PHC: $argon2id$v=19$m=<KiB>,t=<iteraciones>,p=<hilos>$<sal>$<hash>
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// Límites de los parámetros aceptados al leer un hash: argon2.IDKey entra en pánico con p=0
+// y un m enorme agotaría la memoria del servidor en un solo login
+const (
+	Argon2idMaxMemoryKB   = 1024 * 1024
+	Argon2idMaxIterations = 64
+	argon2idMaxKeyLength  = 1024
+)
+
+// argon2idParams son los parámetros leídos de un hash existente
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+func (h *Argon2idHasher) Verify(hash string, password string) bool {
+	params, err := parseArgon2id(hash)
+	if err != nil {
+		return false
+	}
+	key := argon2.IDKey([]byte(password), params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+	return subtle.ConstantTimeCompare(key, params.key) == 1
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.memory != h.Memory || params.iterations != h.Iterations || params.parallelism != h.Parallelism ||
+		uint32(len(params.salt)) != h.SaltLength || uint32(len(params.key)) != h.KeyLength
+}
+
+func parseArgon2id(hash string) (*argon2idParams, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", sal, hash
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrUnknownHashFormat
+	}
+
+	var params argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, ErrUnknownHashFormat
+	}
+	if params.parallelism < 1 || params.iterations < 1 || params.iterations > Argon2idMaxIterations ||
+		params.memory < 8*uint32(params.parallelism) || params.memory > Argon2idMaxMemoryKB {
+		return nil, ErrUnknownHashFormat
+	}
+
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnknownHashFormat
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 || len(params.key) > argon2idMaxKeyLength {
+		return nil, ErrUnknownHashFormat
+	}
+	return &params, nil
+}
+
+// PasswordHashing genera los hashes nuevos con el algoritmo preferido y sigue
+// verificando los hashes de los algoritmos anteriores
+type PasswordHashing struct {
+	preferred PasswordHasher
+	hashers   []PasswordHasher
+}
+
+func NewPasswordHashing(preferred PasswordHasher, legacy ...PasswordHasher) *PasswordHashing {
+	return &PasswordHashing{preferred: preferred, hashers: append([]PasswordHasher{preferred}, legacy...)}
+}
+
+// Hash genera el hash de la contraseña con el algoritmo preferido
+func (ph *PasswordHashing) Hash(password string) (string, error) {
+	return ph.preferred.Hash(password)
+}
+
+// Recognizes indica si el hash pertenece a algún algoritmo configurado, es decir,
+// si la cuenta tiene una contraseña con la que se pueda iniciar sesión
+func (ph *PasswordHashing) Recognizes(hash string) bool {
+	for _, hasher := range ph.hashers {
+		if hasher.Recognizes(hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify compara la contraseña con el hash guardado. rehash indica que la contraseña es correcta
+// pero el hash usa otro algoritmo o parámetros anteriores y conviene regenerarlo.
+// Un hash que ningún algoritmo reconoce (p. ej. el de una cuenta anonimizada) nunca coincide.
+func (ph *PasswordHashing) Verify(hash string, password string) (ok bool, rehash bool) {
+	for _, hasher := range ph.hashers {
+		if !hasher.Recognizes(hash) {
+			continue
+		}
+		if !hasher.Verify(hash, password) {
+			return false, false
+		}
+		return true, hasher != ph.preferred || hasher.NeedsRehash(hash)
+	}
+	return false, false
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Parámetros bajos para que las pruebas sean rápidas
+func newTestArgon2id() *Argon2idHasher {
+	return &Argon2idHasher{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func mustHash(t *testing.T, hasher PasswordHasher, password string) string {
+	t.Helper()
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("error al generar el hash: %v", err)
+	}
+	return hash
+}
+
+func TestVerifyRehashesBcryptWhenCostChanges(t *testing.T) {
+	oldHash := mustHash(t, &BcryptHasher{Cost: bcrypt.MinCost}, "contraseña-segura")
+
+	sameCost := NewPasswordHashing(&BcryptHasher{Cost: bcrypt.MinCost})
+	if ok, rehash := sameCost.Verify(oldHash, "contraseña-segura"); !ok || rehash {
+		t.Errorf("mismo costo: ok=%t rehash=%t, se esperaba ok=true rehash=false", ok, rehash)
+	}
+
+	higherCost := NewPasswordHashing(&BcryptHasher{Cost: bcrypt.MinCost + 1})
+	if ok, rehash := higherCost.Verify(oldHash, "contraseña-segura"); !ok || !rehash {
+		t.Errorf("costo mayor: ok=%t rehash=%t, se esperaba ok=true rehash=true", ok, rehash)
+	}
+	if ok, rehash := higherCost.Verify(oldHash, "otra"); ok || rehash {
+		t.Errorf("contraseña incorrecta: ok=%t rehash=%t, se esperaba false", ok, rehash)
+	}
+}
+
+func TestVerifyMigratesBcryptToArgon2id(t *testing.T) {
+	argon := newTestArgon2id()
+	hashing := NewPasswordHashing(argon, &BcryptHasher{Cost: bcrypt.MinCost})
+
+	legacy := mustHash(t, &BcryptHasher{Cost: bcrypt.MinCost}, "contraseña-segura")
+	if ok, rehash := hashing.Verify(legacy, "contraseña-segura"); !ok || !rehash {
+		t.Fatalf("hash bcrypt: ok=%t rehash=%t, se esperaba ok=true rehash=true", ok, rehash)
+	}
+	if ok, _ := hashing.Verify(legacy, "otra"); ok {
+		t.Error("hash bcrypt aceptó una contraseña incorrecta")
+	}
+
+	// Tras regenerar, el hash nuevo es argon2id y ya no pide otra migración
+	migrated, err := hashing.Hash("contraseña-segura")
+	if err != nil {
+		t.Fatalf("error al generar el hash: %v", err)
+	}
+	if !strings.HasPrefix(migrated, argon2idPrefix) {
+		t.Fatalf("hash = %q, se esperaba argon2id", migrated)
+	}
+	if ok, rehash := hashing.Verify(migrated, "contraseña-segura"); !ok || rehash {
+		t.Errorf("hash argon2id: ok=%t rehash=%t, se esperaba ok=true rehash=false", ok, rehash)
+	}
+
+	stronger := NewPasswordHashing(&Argon2idHasher{Memory: 128, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if ok, rehash := stronger.Verify(migrated, "contraseña-segura"); !ok || !rehash {
+		t.Errorf("parámetros nuevos: ok=%t rehash=%t, se esperaba ok=true rehash=true", ok, rehash)
+	}
+}
+
+func TestVerifyRejectsUnrecognizedHashes(t *testing.T) {
+	hashing := NewPasswordHashing(newTestArgon2id(), &BcryptHasher{Cost: bcrypt.MinCost})
+	anonymized := "!" + strings.Repeat("x", 22)
+
+	for _, hash := range []string{anonymized, "!", "", "contraseña-segura", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5", "$1$md5$hash"} {
+		if hashing.Recognizes(hash) {
+			t.Errorf("Recognizes(%q) = true, se esperaba false", hash)
+		}
+		if ok, rehash := hashing.Verify(hash, hash); ok || rehash {
+			t.Errorf("Verify(%q): ok=%t rehash=%t, se esperaba false", hash, ok, rehash)
+		}
+	}
+}
+
+func TestParseArgon2idRejectsUnsafeParameters(t *testing.T) {
+	const salt, key = "c2FsdHNhbHRzYWx0c2FsdA", "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5"
+
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"sin hilos", "m=64,t=1,p=0"},
+		{"sin iteraciones", "m=64,t=0,p=1"},
+		{"sin memoria", "m=0,t=1,p=1"},
+		{"memoria menor que 8 por hilo", "m=8,t=1,p=2"},
+		{"memoria excesiva", "m=4194304,t=1,p=1"},
+		{"iteraciones excesivas", "m=64,t=100000,p=1"},
+		{"hilos fuera de rango", "m=64,t=1,p=300"},
+		{"negativos", "m=-1,t=1,p=1"},
+	}
+
+	argon := newTestArgon2id()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := argon2idPrefix + "v=19$" + tt.params + "$" + salt + "$" + key
+			if _, err := parseArgon2id(hash); err != ErrUnknownHashFormat {
+				t.Fatalf("parseArgon2id = %v, se esperaba %v", err, ErrUnknownHashFormat)
+			}
+			// Verify no debe entrar en pánico ni aceptar el hash
+			if argon.Verify(hash, "contraseña") {
+				t.Error("Verify aceptó un hash con parámetros inválidos")
+			}
+			if !argon.NeedsRehash(hash) {
+				t.Error("NeedsRehash = false para un hash inválido")
+			}
+		})
+	}
+
+	valid := argon2idPrefix + "v=19$m=64,t=1,p=1$" + salt + "$" + key
+	if _, err := parseArgon2id(valid); err != nil {
+		t.Errorf("parseArgon2id(válido) = %v, se esperaba nil", err)
+	}
+}
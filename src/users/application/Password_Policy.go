@@ -0,0 +1,164 @@
+package application
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcrypt ignora todo lo que pase de 72 bytes, así que una contraseña más larga no aporta seguridad
+const BcryptMaxPasswordBytes = 72
+
+// Códigos de las violaciones de la política de contraseñas
+const (
+	CodePasswordTooShort     = "PASSWORD_TOO_SHORT"
+	CodePasswordTooLong      = "PASSWORD_TOO_LONG"
+	CodePasswordNoUpper      = "PASSWORD_NO_UPPERCASE"
+	CodePasswordNoLower      = "PASSWORD_NO_LOWERCASE"
+	CodePasswordNoDigit      = "PASSWORD_NO_DIGIT"
+	CodePasswordNoSymbol     = "PASSWORD_NO_SYMBOL"
+	CodePasswordPersonalInfo = "PASSWORD_CONTAINS_PERSONAL_INFO"
+	CodePasswordCompromised  = "PASSWORD_COMPROMISED"
+)
+
+// FieldError describe por qué un campo de la petición no es válido
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError agrupa todas las reglas que incumple una contraseña
+type PasswordPolicyError struct {
+	Violations []FieldError
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return "la contraseña no cumple la política: " + strings.Join(messages, "; ")
+}
+
+// PasswordPolicy define los requisitos de las contraseñas elegidas por los usuarios
+type PasswordPolicy struct {
+	MinLength            int
+	MaxBytes             int
+	RequireUpper         bool
+	RequireLower         bool
+	RequireDigit         bool
+	RequireSymbol        bool
+	DisallowPersonalInfo bool
+	RejectCompromised    bool
+}
+
+// Validate comprueba la contraseña contra la política; userName y email son los del dueño de la cuenta.
+// Devuelve un *PasswordPolicyError con todas las violaciones encontradas.
+func (p *PasswordPolicy) Validate(field string, password string, userName string, email string) error {
+	var violations []FieldError
+	add := func(code string, message string) {
+		violations = append(violations, FieldError{Field: field, Code: code, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		add(CodePasswordTooShort, fmt.Sprintf("debe tener al menos %d caracteres", p.MinLength))
+	}
+	if len(password) > p.maxBytes() {
+		add(CodePasswordTooLong, fmt.Sprintf("no puede superar los %d bytes", p.maxBytes()))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add(CodePasswordNoUpper, "debe incluir al menos una letra mayúscula")
+	}
+	if p.RequireLower && !hasLower {
+		add(CodePasswordNoLower, "debe incluir al menos una letra minúscula")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(CodePasswordNoDigit, "debe incluir al menos un número")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(CodePasswordNoSymbol, "debe incluir al menos un símbolo")
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, userName, email) {
+		add(CodePasswordPersonalInfo, "no puede contener tu nombre de usuario ni tu email")
+	}
+	if p.RejectCompromised && isCompromisedPassword(password) {
+		add(CodePasswordCompromised, "es una contraseña común o filtrada; elige otra")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func (p *PasswordPolicy) maxBytes() int {
+	if p.MaxBytes <= 0 || p.MaxBytes > BcryptMaxPasswordBytes {
+		return BcryptMaxPasswordBytes
+	}
+	return p.MaxBytes
+}
+
+// Fragmentos más cortos que esto dan demasiados falsos positivos
+const minPersonalInfoLength = 3
+
+// containsPersonalInfo indica si la contraseña incluye el username, el email o la parte local del email
+func containsPersonalInfo(password string, userName string, email string) bool {
+	lowered := strings.ToLower(password)
+	candidates := []string{userName, email}
+	if local, _, found := strings.Cut(email, "@"); found {
+		candidates = append(candidates, local)
+	}
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if len(candidate) >= minPersonalInfoLength && strings.Contains(lowered, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lista incluida en el binario con contraseñas comunes y filtradas, una por línea y en minúsculas
+//
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var (
+	commonPasswordsOnce sync.Once
+	commonPasswords     map[string]struct{}
+)
+
+// isCompromisedPassword busca la contraseña en la lista sin conexión, sin distinguir mayúsculas
+func isCompromisedPassword(password string) bool {
+	commonPasswordsOnce.Do(func() {
+		commonPasswords = make(map[string]struct{})
+		scanner := bufio.NewScanner(strings.NewReader(commonPasswordsFile))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				commonPasswords[strings.ToLower(line)] = struct{}{}
+			}
+		}
+	})
+	_, found := commonPasswords[strings.ToLower(password)]
+	return found
+}
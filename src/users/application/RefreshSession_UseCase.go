@@ -0,0 +1,68 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"time"
+)
+
+type RefreshSession struct {
+	db            domain.IUser
+	tokens        *TokenService
+	refreshTokens *RefreshTokenService
+	sessions      *SessionService
+}
+
+// RefreshResult contiene el nuevo par de tokens emitido tras la rotación
+type RefreshResult struct {
+	AccessToken      string
+	ExpiresAt        time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+func NewRefreshSession(db domain.IUser, tokens *TokenService, refreshTokens *RefreshTokenService, sessions *SessionService) *RefreshSession {
+	return &RefreshSession{db: db, tokens: tokens, refreshTokens: refreshTokens, sessions: sessions}
+}
+
+func (rs *RefreshSession) Execute(refreshToken string, clientIP string) (*RefreshResult, error) {
+	rotated, newRefreshToken, refreshExpiresAt, err := rs.refreshTokens.Rotate(refreshToken)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		// La familia del token es la sesión: se cierra entera para que sus access tokens dejen de valer
+		if revokeErr := rs.sessions.RevokeFamily(rotated.UserID, rotated.FamilyID); revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := rs.db.GetByID(rotated.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Estado {
+		if err := rs.sessions.RevokeAllForUser(user.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountDeactivated
+	}
+
+	// La familia del refresh token identifica la sesión; también actualiza su última actividad
+	if _, err := rs.sessions.Validate(rotated.FamilyID, user.ID, clientIP); err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := rs.tokens.Generate(user, rotated.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefreshResult{
+		AccessToken:      accessToken,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     newRefreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
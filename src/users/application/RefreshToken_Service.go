@@ -0,0 +1,108 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"expresApi/src/users/domain"
+	"log"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token inválido")
+	ErrRefreshTokenReused  = errors.New("refresh token reutilizado, la sesión fue revocada")
+)
+
+// RefreshTokenService emite y rota refresh tokens de larga duración
+type RefreshTokenService struct {
+	repo domain.IRefreshToken
+	ttl  time.Duration
+}
+
+func NewRefreshTokenService(repo domain.IRefreshToken, ttl time.Duration) *RefreshTokenService {
+	return &RefreshTokenService{repo: repo, ttl: ttl}
+}
+
+// Issue crea un refresh token para el usuario; si familyID está vacío inicia una familia nueva
+func (rs *RefreshTokenService) Issue(userID int32, familyID string) (string, time.Time, error) {
+	if familyID == "" {
+		id, err := randomToken(16)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		familyID = id
+	}
+
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token := &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashToken(raw),
+		ExpiresAt: time.Now().UTC().Add(rs.ttl),
+	}
+	if err := rs.repo.Save(token); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return raw, token.ExpiresAt, nil
+}
+
+// Rotate invalida el token recibido y emite uno nuevo de la misma familia.
+// Si el token ya había sido rotado devuelve el token junto con ErrRefreshTokenReused para que
+// el llamador cierre la sesión completa, no solo la familia de tokens.
+func (rs *RefreshTokenService) Rotate(raw string) (*domain.RefreshToken, string, time.Time, error) {
+	token, err := rs.repo.GetByHash(HashToken(raw))
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	if token == nil || token.RevokedAt != nil || token.IsExpired() {
+		return nil, "", time.Time{}, ErrInvalidRefreshToken
+	}
+
+	rotated, err := rs.repo.MarkRotated(token.ID)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	if !rotated {
+		log.Printf("[Auth] - Reutilización de refresh token detectada para el usuario ID: %d, revocando sesión %s", token.UserID, token.FamilyID)
+		return token, "", time.Time{}, ErrRefreshTokenReused
+	}
+
+	newRaw, expiresAt, err := rs.Issue(token.UserID, token.FamilyID)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	return token, newRaw, expiresAt, nil
+}
+
+// RevokeFamily revoca todos los refresh tokens de una sesión
+func (rs *RefreshTokenService) RevokeFamily(familyID string) error {
+	return rs.repo.RevokeFamily(familyID)
+}
+
+// RevokeAllForUser revoca todos los refresh tokens vigentes del usuario
+func (rs *RefreshTokenService) RevokeAllForUser(userID int32) error {
+	return rs.repo.RevokeAllForUser(userID)
+}
+
+// HashToken calcula el SHA-256 en hexadecimal de un token opaco
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken genera un token aleatorio codificado en base64 URL
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"expresApi/src/users/domain"
+	"time"
+)
+
+// LoginResult contiene el usuario autenticado y sus tokens, o el desafío de 2FA pendiente
+type LoginResult struct {
+	User             *domain.User
+	SessionID        string
+	AccessToken      string
+	ExpiresAt        time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+
+	TwoFactorRequired  bool
+	Challenge          string
+	ChallengeExpiresAt time.Time
+}
+
+// SessionIssuer crea la sesión de servidor y emite el access token y el refresh token de un login completado
+type SessionIssuer struct {
+	tokens        *TokenService
+	refreshTokens *RefreshTokenService
+	sessions      *SessionService
+}
+
+func NewSessionIssuer(tokens *TokenService, refreshTokens *RefreshTokenService, sessions *SessionService) *SessionIssuer {
+	return &SessionIssuer{tokens: tokens, refreshTokens: refreshTokens, sessions: sessions}
+}
+
+func (si *SessionIssuer) Issue(user *domain.User, client ClientInfo) (*LoginResult, error) {
+	// Registrar la sesión con el dispositivo y la IP del cliente
+	session, err := si.sessions.Start(user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Firmar el access token ligado a la sesión
+	accessToken, expiresAt, err := si.tokens.Generate(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// La familia de rotación de los refresh tokens es la propia sesión
+	refreshToken, refreshExpiresAt, err := si.refreshTokens.Issue(user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{
+		User:             user,
+		SessionID:        session.ID,
+		AccessToken:      accessToken,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"log"
+	"time"
+)
+
+var ErrSessionRevoked = errors.New("la sesión fue cerrada o expiró")
+
+// Longitud máxima del User-Agent guardado en la sesión
+const maxUserAgentLength = 255
+
+// Intervalo mínimo entre actualizaciones de last_seen_at para no escribir en cada petición
+const sessionTouchInterval = time.Minute
+
+// ClientInfo identifica el dispositivo desde el que se hace una petición
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionService crea, valida y revoca las sesiones de servidor
+type SessionService struct {
+	repo          domain.ISession
+	refreshTokens *RefreshTokenService
+	hub           domain.IConnectionHub
+	ttl           time.Duration
+}
+
+// NewSessionService crea el servicio; una sesión expira tras ttl sin actividad (la vida del refresh token)
+func NewSessionService(repo domain.ISession, refreshTokens *RefreshTokenService, hub domain.IConnectionHub, ttl time.Duration) *SessionService {
+	return &SessionService{repo: repo, refreshTokens: refreshTokens, hub: hub, ttl: ttl}
+}
+
+// Start registra una nueva sesión para el usuario
+func (ss *SessionService) Start(userID int32, client ClientInfo) (*domain.Session, error) {
+	id, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now().UTC()
+	session := &domain.Session{
+		ID:         id,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := ss.repo.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Validate comprueba que la sesión siga activa y actualiza su última actividad
+func (ss *SessionService) Validate(id string, userID int32, clientIP string) (*domain.Session, error) {
+	session, err := ss.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil || ss.isExpired(session) {
+		return nil, ErrSessionRevoked
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval || session.IP != clientIP {
+		if err := ss.repo.Touch(id, clientIP); err != nil {
+			log.Printf("[Auth] - No se pudo actualizar la sesión %s: %v", id, err)
+		}
+	}
+	return session, nil
+}
+
+// List devuelve las sesiones activas del usuario marcando la actual
+func (ss *SessionService) List(userID int32, currentID string) ([]domain.Session, error) {
+	sessions, err := ss.repo.ListActiveByUser(userID, time.Now().UTC().Add(-ss.ttl))
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// History devuelve todas las sesiones del usuario, incluidas las cerradas
+func (ss *SessionService) History(userID int32) ([]domain.Session, error) {
+	return ss.repo.ListByUser(userID)
+}
+
+// Revoke cierra una sesión del usuario, sus refresh tokens y sus conexiones WebSocket
+func (ss *SessionService) Revoke(userID int32, id string) error {
+	session, err := ss.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+
+	revoked, err := ss.repo.Revoke(id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.ErrSessionNotFound
+	}
+	if err := ss.refreshTokens.RevokeFamily(id); err != nil {
+		return err
+	}
+
+	ss.hub.DisconnectSession(id)
+	log.Printf("[Auth] - Sesión %s cerrada para el usuario ID: %d", id, userID)
+	return nil
+}
+
+// RevokeFamily cierra la sesión ligada a una familia de refresh tokens aunque ya no esté activa,
+// revoca toda la familia y desconecta sus clientes WebSocket
+func (ss *SessionService) RevokeFamily(userID int32, familyID string) error {
+	session, err := ss.repo.GetByID(familyID)
+	if err != nil {
+		return err
+	}
+	if session != nil && session.UserID == userID {
+		if _, err := ss.repo.Revoke(familyID); err != nil {
+			return err
+		}
+	}
+	if err := ss.refreshTokens.RevokeFamily(familyID); err != nil {
+		return err
+	}
+
+	ss.hub.DisconnectSession(familyID)
+	log.Printf("[Auth] - Sesión %s revocada para el usuario ID: %d", familyID, userID)
+	return nil
+}
+
+// RevokeOthers cierra todas las sesiones del usuario excepto la actual y devuelve cuántas se cerraron
+func (ss *SessionService) RevokeOthers(userID int32, currentID string) (int, error) {
+	sessions, err := ss.repo.ListActiveByUser(userID, time.Now().UTC().Add(-ss.ttl))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, session := range sessions {
+		if session.ID == currentID {
+			continue
+		}
+		if err := ss.Revoke(userID, session.ID); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// RevokeAllForUser cierra todas las sesiones del usuario y desconecta sus clientes WebSocket
+func (ss *SessionService) RevokeAllForUser(userID int32) error {
+	if err := ss.repo.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	if err := ss.refreshTokens.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+
+	ss.hub.DisconnectUser(userID)
+	log.Printf("[Auth] - Todas las sesiones cerradas para el usuario ID: %d", userID)
+	return nil
+}
+
+func (ss *SessionService) isExpired(session *domain.Session) bool {
+	return time.Since(session.LastSeenAt) > ss.ttl
+}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"expresApi/src/users/domain"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("token inválido")
+	ErrExpiredToken = errors.New("token expirado")
+)
+
+// TokenClaims contiene los datos firmados dentro del access token
+type TokenClaims struct {
+	UserID    int32  `json:"user_id"`
+	UserName  string `json:"username"`
+	Estado    bool   `json:"estado"`
+	SessionID string `json:"sid,omitempty"`
+	Purpose   string `json:"purpose,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+
+	// ImpersonatorID es el administrador que actúa como el usuario; SessionID es entonces su sesión
+	ImpersonatorID int32 `json:"imp,omitempty"`
+}
+
+// Propósito de los tokens de desafío del segundo factor; no sirven como access token
+const purposeTwoFactor = "2fa"
+
+// TokenService genera y verifica access tokens JWT firmados con HS256
+type TokenService struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenService(secret string, ttl time.Duration) *TokenService {
+	return &TokenService{secret: []byte(secret), ttl: ttl}
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Generate firma un access token de la sesión indicada y devuelve su fecha de expiración
+func (ts *TokenService) Generate(user *domain.User, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	return ts.sign(TokenClaims{
+		UserID:    user.ID,
+		UserName:  user.UserName,
+		Estado:    user.Estado,
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ts.ttl).Unix(),
+	})
+}
+
+// GenerateImpersonation firma un access token de corta duración para que un administrador actúe como user
+func (ts *TokenService) GenerateImpersonation(user *domain.User, impersonatorID int32, sessionID string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	return ts.sign(TokenClaims{
+		UserID:         user.ID,
+		UserName:       user.UserName,
+		Estado:         user.Estado,
+		SessionID:      sessionID,
+		IssuedAt:       now.Unix(),
+		ExpiresAt:      now.Add(ttl).Unix(),
+		ImpersonatorID: impersonatorID,
+	})
+}
+
+// GenerateChallenge firma un desafío de corta duración para completar el login con 2FA
+func (ts *TokenService) GenerateChallenge(user *domain.User, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	return ts.sign(TokenClaims{
+		UserID:    user.ID,
+		UserName:  user.UserName,
+		Estado:    user.Estado,
+		Purpose:   purposeTwoFactor,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+}
+
+// Parse verifica un access token; los desafíos de 2FA y los tokens sin sesión se rechazan
+func (ts *TokenService) Parse(token string) (*TokenClaims, error) {
+	claims, err := ts.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" || claims.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ParseChallenge verifica un desafío de 2FA emitido por GenerateChallenge
+func (ts *TokenService) ParseChallenge(token string) (*TokenClaims, error) {
+	claims, err := ts.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purposeTwoFactor {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (ts *TokenService) sign(claims TokenClaims) (string, time.Time, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + ts.signature(unsigned), time.Unix(claims.ExpiresAt, 0), nil
+}
+
+// verify comprueba la firma y la expiración del token y devuelve sus claims
+func (ts *TokenService) verify(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+
+	expected := ts.signature(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func (ts *TokenService) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, ts.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"encoding/base64"
+	"errors"
+	"expresApi/src/users/domain"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTokenService() *TokenService {
+	return NewTokenService("secreto-de-prueba", 15*time.Minute)
+}
+
+var testUser = &domain.User{ID: 7, UserName: "ana", Estado: true}
+
+func TestParseAcceptsSessionToken(t *testing.T) {
+	ts := newTestTokenService()
+	token, expiresAt, err := ts.Generate(testUser, "sesion-1")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	claims, err := ts.Parse(token)
+	if err != nil {
+		t.Fatalf("Parse: error inesperado: %v", err)
+	}
+	if claims.UserID != testUser.ID || claims.UserName != testUser.UserName || claims.SessionID != "sesion-1" {
+		t.Errorf("claims = %+v, no coinciden con el usuario y la sesión", claims)
+	}
+	if claims.ExpiresAt != expiresAt.Unix() {
+		t.Errorf("exp = %d, se esperaba %d", claims.ExpiresAt, expiresAt.Unix())
+	}
+}
+
+func TestParseRejectsInvalidTokens(t *testing.T) {
+	ts := newTestTokenService()
+	now := time.Now()
+
+	challenge, _, err := ts.GenerateChallenge(testUser, time.Minute)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	withoutSession, _, err := ts.sign(TokenClaims{UserID: testUser.ID, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	expired, _, err := ts.sign(TokenClaims{UserID: testUser.ID, SessionID: "sesion-1", IssuedAt: now.Add(-time.Hour).Unix(), ExpiresAt: now.Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	valid, _, err := ts.Generate(testUser, "sesion-1")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	parts := strings.Split(valid, ".")
+
+	// Cambiar el usuario sin volver a firmar
+	forgedPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"user_id":1,"username":"admin","estado":true,"sid":"sesion-1","exp":` + strconv.FormatInt(now.Add(time.Hour).Unix(), 10) + `}`))
+	otherSecret, _, err := NewTokenService("otro-secreto", time.Minute).Generate(testUser, "sesion-1")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"desafío de 2FA", challenge, ErrInvalidToken},
+		{"sin sid", withoutSession, ErrInvalidToken},
+		{"expirado", expired, ErrExpiredToken},
+		{"payload alterado", parts[0] + "." + forgedPayload + "." + parts[2], ErrInvalidToken},
+		{"firma alterada", parts[0] + "." + parts[1] + "." + flipLastChar(parts[2]), ErrInvalidToken},
+		{"sin firma", parts[0] + "." + parts[1] + ".", ErrInvalidToken},
+		{"firmado con otro secreto", otherSecret, ErrInvalidToken},
+		{"algoritmo none", base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + ".", ErrInvalidToken},
+		{"mal formado", "no.es-un-token", ErrInvalidToken},
+		{"vacío", "", ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ts.Parse(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Parse = (%+v, %v), se esperaba %v", claims, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChallengeOnlyAcceptsChallenges(t *testing.T) {
+	ts := newTestTokenService()
+
+	challenge, _, err := ts.GenerateChallenge(testUser, time.Minute)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	claims, err := ts.ParseChallenge(challenge)
+	if err != nil {
+		t.Fatalf("ParseChallenge: error inesperado: %v", err)
+	}
+	if claims.UserID != testUser.ID || claims.SessionID != "" {
+		t.Errorf("claims = %+v, se esperaba un desafío sin sesión del usuario %d", claims, testUser.ID)
+	}
+
+	access, _, err := ts.Generate(testUser, "sesion-1")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if _, err := ts.ParseChallenge(access); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("ParseChallenge(access token) = %v, se esperaba %v", err, ErrInvalidToken)
+	}
+
+	expired, _, err := ts.GenerateChallenge(testUser, -time.Second)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if _, err := ts.ParseChallenge(expired); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("ParseChallenge(expirado) = %v, se esperaba %v", err, ErrExpiredToken)
+	}
+}
+
+// flipLastChar cambia el último carácter de la firma por otro válido en base64url
+func flipLastChar(s string) string {
+	last := s[len(s)-1]
+	replacement := byte('A')
+	if last == 'A' {
+		replacement = 'B'
+	}
+	return s[:len(s)-1] + string(replacement)
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+// Secreto "12345678901234567890" de los vectores de prueba del RFC 6238 (SHA1), en base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCodeRFC6238Vectors(t *testing.T) {
+	// El RFC publica códigos de 8 dígitos; los de 6 son sus últimos 6 dígitos
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := totpCode(rfc6238Secret, tt.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("totpCode(%d): error inesperado: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("totpCode(%d) = %s, se esperaba %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestTotpCodeAcceptsLowercaseSecret(t *testing.T) {
+	got, err := totpCode("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", 1)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got != "287082" {
+		t.Errorf("código = %s, se esperaba 287082", got)
+	}
+}
+
+func TestVerifyTotpSkewWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name   string
+		offset int64
+		valid  bool
+	}{
+		{"paso actual", 0, true},
+		{"un paso antes", -1, true},
+		{"un paso después", 1, true},
+		{"dos pasos antes", -2, false},
+		{"dos pasos después", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totpCode(rfc6238Secret, current+tt.offset)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			step, ok := verifyTotp(rfc6238Secret, code, now)
+			if ok != tt.valid {
+				t.Fatalf("verifyTotp = %t, se esperaba %t", ok, tt.valid)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("paso = %d, se esperaba %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestVerifyTotpRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	for _, code := range []string{"", "05047", "0504711", "abcdef"} {
+		if _, ok := verifyTotp(rfc6238Secret, code, now); ok {
+			t.Errorf("verifyTotp(%q) aceptó un código inválido", code)
+		}
+	}
+	if _, ok := verifyTotp("no es base32!", "050471", now); ok {
+		t.Error("verifyTotp aceptó un secreto inválido")
+	}
+	if _, ok := verifyTotp(rfc6238Secret, " 050471 ", now); !ok {
+		t.Error("verifyTotp debería ignorar los espacios alrededor del código")
+	}
+}
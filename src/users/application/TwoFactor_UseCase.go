@@ -0,0 +1,214 @@
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"expresApi/src/users/domain"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("la autenticación en dos pasos ya está activa")
+	ErrTwoFactorNotEnrolled    = errors.New("no hay una inscripción de dos pasos pendiente")
+	ErrInvalidTwoFactorCode    = &AuthError{Code: "INVALID_2FA_CODE", Message: "código de verificación inválido"}
+	ErrInvalidChallenge        = &AuthError{Code: "INVALID_2FA_CHALLENGE", Message: "el desafío de dos pasos es inválido o expiró"}
+)
+
+const recoveryCodeCount = 10
+
+type EnrollTwoFactor struct {
+	db        domain.IUser
+	twoFactor domain.ITwoFactor
+	issuer    string
+}
+
+func NewEnrollTwoFactor(db domain.IUser, twoFactor domain.ITwoFactor, issuer string) *EnrollTwoFactor {
+	return &EnrollTwoFactor{db: db, twoFactor: twoFactor, issuer: issuer}
+}
+
+// Execute genera un secreto TOTP pendiente de confirmar y devuelve el secreto y su URI otpauth://
+func (et *EnrollTwoFactor) Execute(userID int32) (string, string, error) {
+	user, err := et.db.GetByID(userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	current, err := et.twoFactor.GetByUserID(userID)
+	if err != nil {
+		return "", "", err
+	}
+	if current != nil && current.Enabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := generateTotpSecret()
+	if err != nil {
+		return "", "", err
+	}
+	if err := et.twoFactor.SaveSecret(userID, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, totpURI(et.issuer, user.UserName, secret), nil
+}
+
+type ConfirmTwoFactor struct {
+	twoFactor domain.ITwoFactor
+}
+
+func NewConfirmTwoFactor(twoFactor domain.ITwoFactor) *ConfirmTwoFactor {
+	return &ConfirmTwoFactor{twoFactor: twoFactor}
+}
+
+// Execute activa el 2FA si el código es válido y devuelve los códigos de recuperación (se muestran una sola vez)
+func (ct *ConfirmTwoFactor) Execute(userID int32, code string) ([]string, error) {
+	current, err := ct.twoFactor.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if current.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := verifyTotp(current.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := ct.twoFactor.Enable(userID, hashes); err != nil {
+		return nil, err
+	}
+	if _, err := ct.twoFactor.UseStep(userID, step); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Auth] - Autenticación en dos pasos activada para el usuario ID: %d", userID)
+	return codes, nil
+}
+
+type CompleteTwoFactorLogin struct {
+	db        domain.IUser
+	twoFactor domain.ITwoFactor
+	tokens    *TokenService
+	issuer    *SessionIssuer
+	throttle  *LoginThrottle
+}
+
+func NewCompleteTwoFactorLogin(db domain.IUser, twoFactor domain.ITwoFactor, tokens *TokenService, issuer *SessionIssuer, throttle *LoginThrottle) *CompleteTwoFactorLogin {
+	return &CompleteTwoFactorLogin{db: db, twoFactor: twoFactor, tokens: tokens, issuer: issuer, throttle: throttle}
+}
+
+// Execute valida el desafío y el código (TOTP o de recuperación) y emite la sesión
+func (cl *CompleteTwoFactorLogin) Execute(challenge string, code string, client ClientInfo) (*LoginResult, error) {
+	claims, err := cl.tokens.ParseChallenge(challenge)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	if err := cl.throttle.Check(claims.UserName, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := cl.db.GetByID(claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+	if !user.Estado {
+		return nil, ErrAccountDeactivated
+	}
+
+	current, err := cl.twoFactor.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || !current.Enabled {
+		return nil, ErrInvalidChallenge
+	}
+
+	valid, err := cl.verifyCode(current, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		if err := cl.throttle.Failure(user.UserName, client.IP); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	if err := cl.throttle.Success(user.UserName, client.IP); err != nil {
+		return nil, err
+	}
+
+	return cl.issuer.Issue(user, client)
+}
+
+func (cl *CompleteTwoFactorLogin) verifyCode(current *domain.TwoFactor, code string) (bool, error) {
+	if step, ok := verifyTotp(current.Secret, code, time.Now()); ok {
+		// Un mismo código TOTP no puede usarse dos veces
+		return cl.twoFactor.UseStep(current.UserID, step)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	used, err := cl.twoFactor.UseRecoveryCode(current.UserID, HashToken(normalized))
+	if used {
+		log.Printf("[Auth] - Código de recuperación usado por el usuario ID: %d", current.UserID)
+	}
+	return used, err
+}
+
+type ResetTwoFactor struct {
+	db        domain.IUser
+	twoFactor domain.ITwoFactor
+}
+
+func NewResetTwoFactor(db domain.IUser, twoFactor domain.ITwoFactor) *ResetTwoFactor {
+	return &ResetTwoFactor{db: db, twoFactor: twoFactor}
+}
+
+// Execute elimina la configuración de 2FA del usuario (uso administrativo)
+func (rt *ResetTwoFactor) Execute(actorID int32, userID int32) error {
+	if _, err := rt.db.GetByID(userID); err != nil {
+		return err
+	}
+	if err := rt.twoFactor.Disable(userID); err != nil {
+		return err
+	}
+
+	log.Printf("[Auth] - Autenticación en dos pasos restablecida para el usuario ID: %d por el usuario ID: %d", userID, actorID)
+	return nil
+}
+
+// generateRecoveryCodes crea códigos con formato xxxxx-xxxxx y sus hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(buf)
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, HashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
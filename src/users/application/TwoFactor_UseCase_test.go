@@ -0,0 +1,122 @@
+package application
+
+import (
+	"expresApi/src/users/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryTwoFactor imita las garantías del repositorio: pasos y códigos de recuperación de un solo uso
+type memoryTwoFactor struct {
+	domain.ITwoFactor
+	lastStep      int64
+	recoveryCodes map[string]bool
+}
+
+func (m *memoryTwoFactor) UseStep(userID int32, step int64) (bool, error) {
+	if step <= m.lastStep {
+		return false, nil
+	}
+	m.lastStep = step
+	return true, nil
+}
+
+func (m *memoryTwoFactor) UseRecoveryCode(userID int32, codeHash string) (bool, error) {
+	if !m.recoveryCodes[codeHash] {
+		return false, nil
+	}
+	delete(m.recoveryCodes, codeHash)
+	return true, nil
+}
+
+func newTwoFactorFixture(t *testing.T) (*CompleteTwoFactorLogin, *domain.TwoFactor, []string) {
+	t.Helper()
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("error al generar los códigos: %v", err)
+	}
+
+	store := &memoryTwoFactor{recoveryCodes: map[string]bool{}}
+	for _, hash := range hashes {
+		store.recoveryCodes[hash] = true
+	}
+	login := &CompleteTwoFactorLogin{twoFactor: store}
+	current := &domain.TwoFactor{UserID: 1, Secret: rfc6238Secret, Enabled: true}
+	return login, current, codes
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("se generaron %d códigos y %d hashes, se esperaban %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("el código %q no tiene el formato xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("el código %q está repetido", code)
+		}
+		seen[code] = true
+		if hashes[i] != HashToken(normalizeRecoveryCode(code)) {
+			t.Errorf("el hash del código %q no coincide con el del código normalizado", code)
+		}
+	}
+}
+
+func TestRecoveryCodeIsSingleUse(t *testing.T) {
+	login, current, codes := newTwoFactorFixture(t)
+
+	valid, err := login.verifyCode(current, codes[0])
+	if err != nil || !valid {
+		t.Fatalf("primer uso: valid=%t err=%v, se esperaba válido", valid, err)
+	}
+	valid, err = login.verifyCode(current, codes[0])
+	if err != nil || valid {
+		t.Fatalf("segundo uso: valid=%t err=%v, se esperaba rechazado", valid, err)
+	}
+
+	// Los demás códigos siguen siendo válidos, escritos en mayúsculas o sin guion
+	valid, err = login.verifyCode(current, strings.ToUpper(codes[1]))
+	if err != nil || !valid {
+		t.Errorf("código en mayúsculas: valid=%t err=%v, se esperaba válido", valid, err)
+	}
+	valid, err = login.verifyCode(current, strings.ReplaceAll(codes[2], "-", ""))
+	if err != nil || !valid {
+		t.Errorf("código sin guion: valid=%t err=%v, se esperaba válido", valid, err)
+	}
+}
+
+func TestTotpCodeCannotBeReplayed(t *testing.T) {
+	login, current, _ := newTwoFactorFixture(t)
+
+	code, err := totpCode(current.Secret, time.Now().Unix()/totpPeriod)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	valid, err := login.verifyCode(current, code)
+	if err != nil || !valid {
+		t.Fatalf("primer uso: valid=%t err=%v, se esperaba válido", valid, err)
+	}
+	valid, err = login.verifyCode(current, code)
+	if err != nil || valid {
+		t.Fatalf("repetición: valid=%t err=%v, se esperaba rechazado", valid, err)
+	}
+}
+
+func TestVerifyCodeRejectsUnknownCodes(t *testing.T) {
+	login, current, _ := newTwoFactorFixture(t)
+
+	for _, code := range []string{"", "-", "00000-00000", "no-es-un-codigo"} {
+		if valid, err := login.verifyCode(current, code); err != nil || valid {
+			t.Errorf("verifyCode(%q): valid=%t err=%v, se esperaba rechazado", code, valid, err)
+		}
+	}
+}
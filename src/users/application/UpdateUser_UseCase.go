@@ -2,16 +2,108 @@ package application
 
 import (
 	"expresApi/src/users/domain"
+	"strings"
 )
 
 type EditUser struct {
-	db domain.IUser
+	db           domain.IUser
+	verification *EmailVerificationService
+	policy       *PasswordPolicy
+	hashing      *PasswordHashing
+	sessions     *SessionService
 }
 
-func NewEditUser(db domain.IUser) *EditUser {
-	return &EditUser{db: db}
+func NewEditUser(db domain.IUser, verification *EmailVerificationService, policy *PasswordPolicy, hashing *PasswordHashing, sessions *SessionService) *EditUser {
+	return &EditUser{db: db, verification: verification, policy: policy, hashing: hashing, sessions: sessions}
 }
 
+// Execute reemplaza username y email. La contraseña solo se cambia si se envía (el controlador
+// lo limita a administradores), se guarda hasheada y cierra todas las sesiones del usuario.
 func (eu *EditUser) Execute(id int32, userName string, email string, password string) error {
-	return eu.db.UpdateUser(id, userName, email, password)
-}
\ No newline at end of file
+	if err := normalizeField(&userName, ErrUserNameRequired); err != nil {
+		return err
+	}
+	if err := normalizeField(&email, ErrEmailRequired); err != nil {
+		return err
+	}
+
+	current, err := eu.db.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := ensureUnique(eu.db, id, &userName, &email); err != nil {
+		return err
+	}
+
+	changes := domain.UserChanges{UserName: &userName, Email: &email}
+	if password != "" {
+		if err := eu.policy.Validate("password", password, userName, email); err != nil {
+			return err
+		}
+		hashedPassword, err := eu.hashing.Hash(password)
+		if err != nil {
+			return err
+		}
+		changes.Password = &hashedPassword
+	}
+
+	if err := eu.db.UpdateUser(id, changes); err != nil {
+		return err
+	}
+	if changes.Password != nil {
+		// Quien conociera la contraseña anterior no debe conservar el acceso
+		if err := eu.sessions.RevokeAllForUser(id); err != nil {
+			return err
+		}
+	}
+	return sendVerificationOnEmailChange(eu.db, eu.verification, current, &email)
+}
+
+type PatchUser struct {
+	db           domain.IUser
+	verification *EmailVerificationService
+}
+
+func NewPatchUser(db domain.IUser, verification *EmailVerificationService) *PatchUser {
+	return &PatchUser{db: db, verification: verification}
+}
+
+// Execute actualiza solo los campos enviados (nil = sin cambios) y devuelve el usuario resultante
+func (pu *PatchUser) Execute(id int32, userName *string, email *string) (*domain.User, error) {
+	if err := normalizeField(userName, ErrUserNameRequired); err != nil {
+		return nil, err
+	}
+	if err := normalizeField(email, ErrEmailRequired); err != nil {
+		return nil, err
+	}
+
+	current, err := pu.db.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureUnique(pu.db, id, userName, email); err != nil {
+		return nil, err
+	}
+
+	if err := pu.db.UpdateUser(id, domain.UserChanges{UserName: userName, Email: email}); err != nil {
+		return nil, err
+	}
+	if err := sendVerificationOnEmailChange(pu.db, pu.verification, current, email); err != nil {
+		return nil, err
+	}
+
+	return pu.db.GetByID(id)
+}
+
+// sendVerificationOnEmailChange envía el enlace de verificación a la nueva dirección;
+// el repositorio ya la dejó sin verificar al guardarla
+func sendVerificationOnEmailChange(db domain.IUser, verification *EmailVerificationService, previous *domain.User, email *string) error {
+	if email == nil || strings.EqualFold(*email, previous.Email) {
+		return nil
+	}
+	user, err := db.GetByID(previous.ID)
+	if err != nil {
+		return err
+	}
+	return verification.Send(user)
+}
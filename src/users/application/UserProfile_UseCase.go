@@ -0,0 +1,245 @@
+package application
+
+import (
+	"bytes"
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // las zonas horarias se validan aunque el sistema no tenga la base IANA
+	"unicode/utf8"
+)
+
+// Códigos de los errores de validación del perfil
+const (
+	CodeProfileTooLong         = "PROFILE_FIELD_TOO_LONG"
+	CodeProfileInvalidPhone    = "PROFILE_INVALID_PHONE"
+	CodeProfileInvalidLocale   = "PROFILE_INVALID_LOCALE"
+	CodeProfileInvalidTimezone = "PROFILE_INVALID_TIMEZONE"
+)
+
+// ProfileValidationError agrupa los campos del perfil que no son válidos
+type ProfileValidationError struct {
+	Violations []FieldError
+}
+
+func (e *ProfileValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Field + ": " + violation.Message
+	}
+	return "el perfil no es válido: " + strings.Join(messages, "; ")
+}
+
+// ProfileChanges son los campos del perfil a modificar; nil = sin cambios, vacío = borrar
+type ProfileChanges struct {
+	DisplayName *string
+	Phone       *string
+	Locale      *string
+	Timezone    *string
+}
+
+const maxDisplayNameLength = 100
+
+var (
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+	// Formato E.164: + y hasta 15 dígitos
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	// Etiqueta de idioma simple: es, es-MX, pt-BR, zh-Hant-TW
+	localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+)
+
+type UpdateProfile struct {
+	db domain.IUser
+}
+
+func NewUpdateProfile(db domain.IUser) *UpdateProfile {
+	return &UpdateProfile{db: db}
+}
+
+// Execute valida y guarda los campos enviados, normalizando teléfono e idioma, y devuelve el usuario actualizado
+func (up *UpdateProfile) Execute(id int32, changes ProfileChanges) (*domain.User, error) {
+	if _, err := up.db.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	var violations []FieldError
+	add := func(field string, code string, message string) {
+		violations = append(violations, FieldError{Field: field, Code: code, Message: message})
+	}
+
+	if changes.DisplayName != nil {
+		*changes.DisplayName = strings.TrimSpace(*changes.DisplayName)
+		if utf8.RuneCountInString(*changes.DisplayName) > maxDisplayNameLength {
+			add("display_name", CodeProfileTooLong, fmt.Sprintf("no puede superar los %d caracteres", maxDisplayNameLength))
+		}
+	}
+	if changes.Phone != nil {
+		*changes.Phone = phoneSeparators.Replace(strings.TrimSpace(*changes.Phone))
+		if *changes.Phone != "" && !phonePattern.MatchString(*changes.Phone) {
+			add("phone", CodeProfileInvalidPhone, "debe estar en formato internacional, por ejemplo +5215512345678")
+		}
+	}
+	if changes.Locale != nil {
+		*changes.Locale = normalizeLocale(*changes.Locale)
+		if *changes.Locale != "" && !localePattern.MatchString(*changes.Locale) {
+			add("locale", CodeProfileInvalidLocale, "debe ser una etiqueta de idioma como es o es-MX")
+		}
+	}
+	if changes.Timezone != nil {
+		*changes.Timezone = strings.TrimSpace(*changes.Timezone)
+		if *changes.Timezone != "" && !isValidTimezone(*changes.Timezone) {
+			add("timezone", CodeProfileInvalidTimezone, "debe ser una zona horaria IANA como America/Mexico_City")
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &ProfileValidationError{Violations: violations}
+	}
+
+	err := up.db.UpdateUser(id, domain.UserChanges{
+		DisplayName: changes.DisplayName,
+		Phone:       changes.Phone,
+		Locale:      changes.Locale,
+		Timezone:    changes.Timezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return up.db.GetByID(id)
+}
+
+// normalizeLocale acepta es_mx o ES-mx y devuelve es-MX
+func normalizeLocale(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToUpper(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// isValidTimezone acepta solo nombres IANA; "Local" y "UTC" genérico no dicen nada del usuario
+func isValidTimezone(name string) bool {
+	if name == "Local" || !strings.Contains(name, "/") && name != "UTC" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+var (
+	ErrAvatarTooLarge        = errors.New("la imagen supera el tamaño máximo permitido")
+	ErrAvatarUnsupportedType = errors.New("formato de imagen no soportado; usa JPEG, PNG o GIF")
+	ErrAvatarInvalidImage    = errors.New("el archivo no es una imagen válida")
+	ErrAvatarTooManyPixels   = errors.New("la imagen tiene demasiados píxeles")
+)
+
+// Tipos aceptados según el contenido del archivo, no según la extensión ni el encabezado del cliente
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+type UploadAvatar struct {
+	db       domain.IUser
+	blobs    domain.IBlobStore
+	maxBytes int64
+}
+
+func NewUploadAvatar(db domain.IUser, blobs domain.IBlobStore, maxBytes int64) *UploadAvatar {
+	return &UploadAvatar{db: db, blobs: blobs, maxBytes: maxBytes}
+}
+
+// MaxBytes es el tamaño máximo aceptado del archivo original
+func (ua *UploadAvatar) MaxBytes() int64 {
+	return ua.maxBytes
+}
+
+// Execute valida la imagen, la recorta al centro, la guarda en todos los tamaños y reemplaza el avatar anterior
+func (ua *UploadAvatar) Execute(id int32, content []byte) (*domain.User, error) {
+	user, err := ua.db.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > ua.maxBytes {
+		return nil, ErrAvatarTooLarge
+	}
+	if !avatarContentTypes[http.DetectContentType(content)] {
+		return nil, ErrAvatarUnsupportedType
+	}
+
+	resized, err := resizeAvatar(content, domain.AvatarSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cada subida usa una clave nueva para que los navegadores no sirvan la imagen anterior de su caché
+	version, err := randomToken(9)
+	if err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("avatars/%d/%s", id, version)
+	for _, size := range domain.AvatarSizes {
+		if err := ua.blobs.Put(domain.AvatarObject(base, size), "image/png", bytes.NewReader(resized[size])); err != nil {
+			deleteAvatar(ua.blobs, base)
+			return nil, err
+		}
+	}
+
+	url := ua.blobs.URL(base)
+	if err := ua.db.UpdateUser(id, domain.UserChanges{AvatarKey: &base, AvatarURL: &url}); err != nil {
+		deleteAvatar(ua.blobs, base)
+		return nil, err
+	}
+	if user.AvatarKey != "" {
+		deleteAvatar(ua.blobs, user.AvatarKey)
+	}
+
+	return ua.db.GetByID(id)
+}
+
+type DeleteAvatar struct {
+	db    domain.IUser
+	blobs domain.IBlobStore
+}
+
+func NewDeleteAvatar(db domain.IUser, blobs domain.IBlobStore) *DeleteAvatar {
+	return &DeleteAvatar{db: db, blobs: blobs}
+}
+
+func (da *DeleteAvatar) Execute(id int32) error {
+	user, err := da.db.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if user.AvatarKey == "" {
+		return nil
+	}
+
+	empty := ""
+	if err := da.db.UpdateUser(id, domain.UserChanges{AvatarKey: &empty, AvatarURL: &empty}); err != nil {
+		return err
+	}
+	deleteAvatar(da.blobs, user.AvatarKey)
+	return nil
+}
+
+// deleteAvatar borra todos los tamaños; un archivo huérfano no debe hacer fallar la petición
+func deleteAvatar(blobs domain.IBlobStore, base string) {
+	for _, size := range domain.AvatarSizes {
+		if err := blobs.Delete(domain.AvatarObject(base, size)); err != nil {
+			log.Printf("[Avatar] - Error al borrar %s: %v", domain.AvatarObject(base, size), err)
+		}
+	}
+}
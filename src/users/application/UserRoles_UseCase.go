@@ -0,0 +1,88 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+)
+
+type GetUserRoles struct {
+	db domain.IUser
+}
+
+func NewGetUserRoles(db domain.IUser) *GetUserRoles {
+	return &GetUserRoles{db: db}
+}
+
+func (gr *GetUserRoles) Execute(userID int32) ([]domain.Role, error) {
+	if _, err := gr.db.GetByID(userID); err != nil {
+		return nil, err
+	}
+	return gr.db.GetUserRoles(userID)
+}
+
+var ErrSelfAdminRevoke = errors.New("no puedes quitarte el rol de administrador a ti mismo")
+
+type UpdateUserRoles struct {
+	db domain.IUser
+}
+
+func NewUpdateUserRoles(db domain.IUser) *UpdateUserRoles {
+	return &UpdateUserRoles{db: db}
+}
+
+// Execute reemplaza los roles del usuario por los indicados, asignando y revocando solo las diferencias
+func (ur *UpdateUserRoles) Execute(actorID int32, userID int32, roleNames []string) ([]domain.Role, error) {
+	if _, err := ur.db.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	available, err := ur.db.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(available))
+	for _, role := range available {
+		known[role.Name] = true
+	}
+
+	wanted := make(map[string]bool, len(roleNames))
+	for _, name := range roleNames {
+		if !known[name] {
+			return nil, fmt.Errorf("%w: %s", domain.ErrRoleNotFound, name)
+		}
+		wanted[name] = true
+	}
+
+	current, err := ur.db.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	has := make(map[string]bool, len(current))
+	for _, role := range current {
+		has[role.Name] = true
+	}
+
+	// Evitar que un administrador se quede sin acceso a la gestión de roles
+	if actorID == userID && has[domain.RoleAdmin] && !wanted[domain.RoleAdmin] {
+		return nil, ErrSelfAdminRevoke
+	}
+
+	for _, role := range current {
+		if !wanted[role.Name] {
+			if err := ur.db.RevokeRole(userID, role.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name := range wanted {
+		if !has[name] {
+			if err := ur.db.AssignRole(userID, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ur.db.GetUserRoles(userID)
+}
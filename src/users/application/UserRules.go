@@ -0,0 +1,51 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"strings"
+)
+
+var (
+	ErrUserNameRequired = errors.New("el nombre de usuario es requerido")
+	ErrEmailRequired    = errors.New("el email es requerido")
+	ErrPasswordRequired = errors.New("la contraseña es requerida")
+)
+
+// ensureUnique verifica que el username y el email no pertenezcan a otro usuario.
+// id es el usuario que se está editando (0 al crear).
+func ensureUnique(db domain.IUser, id int32, userName *string, email *string) error {
+	if userName != nil {
+		existing, err := db.GetUserByCredentials(*userName)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		if existing != nil && existing.ID != id {
+			return domain.ErrUserNameTaken
+		}
+	}
+
+	if email != nil {
+		existing, err := db.GetByEmail(*email)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		if existing != nil && existing.ID != id {
+			return domain.ErrEmailTaken
+		}
+	}
+
+	return nil
+}
+
+// normalizeField recorta espacios y valida que el campo no quede vacío
+func normalizeField(value *string, required error) error {
+	if value == nil {
+		return nil
+	}
+	*value = strings.TrimSpace(*value)
+	if *value == "" {
+		return required
+	}
+	return nil
+}
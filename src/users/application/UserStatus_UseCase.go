@@ -0,0 +1,57 @@
+package application
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"strings"
+)
+
+var (
+	ErrSelfDeactivation          = errors.New("no puedes desactivar tu propia cuenta")
+	ErrDeactivationReasonMissing = errors.New("el motivo de la desactivación es requerido")
+)
+
+type ActivateUser struct {
+	db domain.IUser
+}
+
+func NewActivateUser(db domain.IUser) *ActivateUser {
+	return &ActivateUser{db: db}
+}
+
+// Execute activa el usuario; es idempotente y devuelve si hubo cambio
+func (au *ActivateUser) Execute(actorID int32, id int32) (bool, error) {
+	return au.db.SetUserStatus(id, true, "", actorID)
+}
+
+type DeactivateUser struct {
+	db       domain.IUser
+	sessions *SessionService
+}
+
+func NewDeactivateUser(db domain.IUser, sessions *SessionService) *DeactivateUser {
+	return &DeactivateUser{db: db, sessions: sessions}
+}
+
+// Execute desactiva el usuario registrando el motivo; es idempotente y devuelve si hubo cambio
+func (du *DeactivateUser) Execute(actorID int32, id int32, reason string) (bool, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return false, ErrDeactivationReasonMissing
+	}
+	if actorID == id {
+		return false, ErrSelfDeactivation
+	}
+
+	changed, err := du.db.SetUserStatus(id, false, reason, actorID)
+	if err != nil {
+		return false, err
+	}
+
+	// Al desactivar un usuario se cierran sus sesiones y sus conexiones WebSocket
+	if err := du.sessions.RevokeAllForUser(id); err != nil {
+		return changed, err
+	}
+
+	return changed, nil
+}
@@ -1,9 +1,48 @@
 package application
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"expresApi/src/users/domain"
 )
 
+const (
+	DefaultUserPageSize = 20
+	MaxUserPageSize     = 100
+)
+
+var (
+	ErrInvalidUserSort   = errors.New("campo de orden no permitido")
+	ErrInvalidUserCursor = errors.New("cursor inválido")
+	ErrInvalidUserPage   = errors.New("limit y offset deben ser positivos")
+)
+
+var userSortFields = map[string]bool{
+	domain.UserSortID:        true,
+	domain.UserSortUserName:  true,
+	domain.UserSortEmail:     true,
+	domain.UserSortCreatedAt: true,
+}
+
+// UserPage es una página del listado de usuarios con el total de resultados
+type UserPage struct {
+	Users      []domain.User
+	Total      int
+	Limit      int
+	Offset     int
+	NextCursor string
+}
+
+// cursorPayload es el contenido codificado en el cursor; incluye el orden para rechazar
+// cursores generados con otro criterio
+type cursorPayload struct {
+	SortBy   string `json:"s"`
+	SortDesc bool   `json:"d"`
+	Value    string `json:"v"`
+	ID       int32  `json:"id"`
+}
+
 type ViewUser struct {
 	db domain.IUser
 }
@@ -12,6 +51,75 @@ func NewViewUser(db domain.IUser) *ViewUser {
 	return &ViewUser{db: db}
 }
 
-func (vu *ViewUser) Execute() ([]domain.User, error) {
-	return vu.db.GetAll()
-}
\ No newline at end of file
+// Execute devuelve una página de usuarios; cursor es el next_cursor de una página anterior
+func (vu *ViewUser) Execute(query domain.UserQuery, cursor string) (*UserPage, error) {
+	if query.SortBy == "" {
+		query.SortBy = domain.UserSortID
+	}
+	if !userSortFields[query.SortBy] {
+		return nil, ErrInvalidUserSort
+	}
+	if query.Limit < 0 || query.Offset < 0 {
+		return nil, ErrInvalidUserPage
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultUserPageSize
+	}
+	if query.Limit > MaxUserPageSize {
+		query.Limit = MaxUserPageSize
+	}
+
+	if cursor != "" {
+		after, err := decodeUserCursor(cursor, query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+		query.Offset = 0
+	}
+
+	total, err := vu.db.CountUsers(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se pide un registro extra para saber si hay una página siguiente
+	pageQuery := query
+	pageQuery.Limit = query.Limit + 1
+	users, err := vu.db.FindUsers(pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Users: users, Total: total, Limit: query.Limit, Offset: query.Offset}
+	if len(users) > query.Limit {
+		page.Users = users[:query.Limit]
+		last := page.Users[len(page.Users)-1]
+		page.NextCursor = encodeUserCursor(query, &last)
+	}
+	if page.Users == nil {
+		page.Users = []domain.User{}
+	}
+	return page, nil
+}
+
+func encodeUserCursor(query domain.UserQuery, last *domain.User) string {
+	payload, _ := json.Marshal(cursorPayload{SortBy: query.SortBy, SortDesc: query.SortDesc, Value: last.SortValue(query.SortBy), ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeUserCursor(cursor string, query domain.UserQuery) (*domain.UserCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidUserCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidUserCursor
+	}
+	if payload.SortBy != query.SortBy || payload.SortDesc != query.SortDesc {
+		return nil, ErrInvalidUserCursor
+	}
+	return &domain.UserCursor{Value: payload.Value, ID: payload.ID}, nil
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrApiKeyNotFound = errors.New("API key no encontrada")
+
+// IApiKey define la persistencia de las API keys (solo se guarda su hash)
+type IApiKey interface {
+	Save(key *ApiKey) error
+	GetByHash(keyHash string) (*ApiKey, error)
+	ListByUser(userID int32) ([]ApiKey, error)
+	// Revoke revoca la key del usuario; devuelve false si no existe o ya estaba revocada
+	Revoke(userID int32, id int64) (bool, error)
+	TouchLastUsed(id int64, ip string) error
+}
+
+type ApiKey struct {
+	ID         int64      `json:"id"`
+	UserID     int32      `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (k *ApiKey) IsActive() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Acciones registradas en la bitácora de auditoría
+const (
+	AuditActionDataExport = "user.data_export"
+	AuditActionErasure    = "user.erasure"
+
+	AuditActionImpersonationStart   = "user.impersonation.start"
+	AuditActionImpersonationRequest = "user.impersonation.request"
+)
+
+// IAuditLog define la persistencia de la bitácora de auditoría; las entradas no se modifican ni se borran
+type IAuditLog interface {
+	Record(entry *AuditEntry) error
+	// ListByUser devuelve las entradas en las que el usuario es el actor o el afectado
+	ListByUser(userID int32) ([]AuditEntry, error)
+}
+
+type AuditEntry struct {
+	ID           int64     `json:"id"`
+	ActorID      int32     `json:"actor_id"`
+	TargetUserID int32     `json:"target_user_id"`
+	Action       string    `json:"action"`
+	Details      string    `json:"details,omitempty"`
+	IP           string    `json:"ip,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
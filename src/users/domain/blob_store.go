@@ -0,0 +1,12 @@
+package domain
+
+import "io"
+
+// IBlobStore guarda archivos públicos (por ejemplo avatares) bajo una clave con forma de ruta
+type IBlobStore interface {
+	Put(key string, contentType string, content io.Reader) error
+	// Delete no devuelve error si la clave no existe
+	Delete(key string) error
+	// URL devuelve la dirección pública desde la que se sirve la clave
+	URL(key string) string
+}
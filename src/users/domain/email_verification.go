@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// IEmailVerification define la persistencia de los tokens de verificación de email (solo su hash)
+type IEmailVerification interface {
+	Save(token *EmailVerificationToken) error
+	GetByHash(tokenHash string) (*EmailVerificationToken, error)
+	// Redeem marca el token como usado y el email del usuario como verificado en una transacción;
+	// devuelve false si el token ya se había usado
+	Redeem(id int64, userID int32) (bool, error)
+	InvalidateForUser(userID int32) error
+}
+
+type EmailVerificationToken struct {
+	ID        int64
+	UserID    int32
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (t *EmailVerificationToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
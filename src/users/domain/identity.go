@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrIdentityNotFound = errors.New("identidad externa no encontrada")
+
+// IUserIdentity define la persistencia de las identidades OIDC vinculadas a los usuarios
+type IUserIdentity interface {
+	Save(identity *UserIdentity) error
+	// GetBySubject devuelve nil si ningún usuario tiene vinculada la identidad
+	GetBySubject(issuer string, subject string) (*UserIdentity, error)
+	ListByUser(userID int32) ([]UserIdentity, error)
+	// Delete desvincula la identidad del usuario; devuelve false si no existe
+	Delete(userID int32, id int64) (bool, error)
+	TouchLastLogin(id int64) error
+
+	// Solicitudes de autorización en curso (state, nonce y verificador PKCE)
+	SaveAuthRequest(request *OIDCAuthRequest) error
+	// ConsumeAuthRequest devuelve la solicitud y la marca como usada; nil si no existe o ya se usó
+	ConsumeAuthRequest(stateHash string) (*OIDCAuthRequest, error)
+}
+
+// UserIdentity vincula un usuario con el sujeto (sub) de un proveedor OIDC
+type UserIdentity struct {
+	ID          int64      `json:"id"`
+	UserID      int32      `json:"user_id"`
+	Issuer      string     `json:"issuer"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// OIDCAuthRequest guarda lo necesario para validar la respuesta del proveedor.
+// LinkUserID es distinto de cero cuando un usuario autenticado vincula una identidad nueva.
+type OIDCAuthRequest struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	LinkUserID   int32
+	ExpiresAt    time.Time
+}
+
+func (r *OIDCAuthRequest) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// IIdentityProvider es un proveedor OpenID Connect configurado por su issuer
+type IIdentityProvider interface {
+	Issuer() string
+	// AuthorizationURL arma la redirección al proveedor con PKCE (S256)
+	AuthorizationURL(state string, nonce string, codeChallenge string) (string, error)
+	// Exchange canjea el código por tokens y devuelve los claims del ID token ya validado
+	// (firma contra el JWKS, issuer, audiencia y expiración)
+	Exchange(code string, codeVerifier string) (*ExternalIdentity, error)
+}
+
+// ExternalIdentity son los claims del ID token que usa la aplicación
+type ExternalIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+	Nonce             string
+}
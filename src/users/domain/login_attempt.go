@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ILoginAttemptStore guarda los intentos fallidos de login por clave (usuario o IP);
+// también cuenta las solicitudes de reenvío de verificación
+type ILoginAttemptStore interface {
+	Get(key string) (*LoginAttempt, error)
+	// RecordFailure suma un fallo; si el último fallo es más antiguo que window el contador se reinicia
+	RecordFailure(key string, window time.Duration) (*LoginAttempt, error)
+	// Lock bloquea la clave hasta until, reinicia los fallos e incrementa LockCount
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LockCount     int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a != nil && now.Before(a.LockedUntil)
+}
+
+// IsStale indica que no hubo fallos ni bloqueos durante ttl; el historial de bloqueos se olvida
+func (a *LoginAttempt) IsStale(now time.Time, ttl time.Duration) bool {
+	lastActivity := a.LastFailureAt
+	if a.LockedUntil.After(lastActivity) {
+		lastActivity = a.LockedUntil
+	}
+	return ttl > 0 && now.Sub(lastActivity) > ttl
+}
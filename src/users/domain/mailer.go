@@ -0,0 +1,6 @@
+package domain
+
+// Mailer envía correos electrónicos transaccionales
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// IPasswordReset define la persistencia de los tokens de restablecimiento (solo su hash)
+type IPasswordReset interface {
+	Save(token *PasswordResetToken) error
+	GetByHash(tokenHash string) (*PasswordResetToken, error)
+	// Redeem marca el token como usado y guarda la contraseña (ya hasheada) en una transacción;
+	// devuelve false si el token ya se había usado
+	Redeem(id int64, userID int32, hashedPassword string) (bool, error)
+	InvalidateForUser(userID int32) error
+}
+
+type PasswordResetToken struct {
+	ID        int64
+	UserID    int32
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
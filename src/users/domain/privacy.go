@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// IUserComments da acceso a los comentarios publicados por un usuario.
+// Lo implementa un adaptador sobre el módulo de comentarios.
+type IUserComments interface {
+	ListByUserID(userID int32) ([]UserComment, error)
+}
+
+// IUserErasure aplica el derecho al olvido en una sola transacción: si algo falla no cambia nada
+type IUserErasure interface {
+	// Erase anonimiza la cuenta, elimina sus credenciales, sesiones y roles, reescribe el autor
+	// de sus comentarios conservando la calificación y registra la entrada de auditoría
+	Erase(erasure *UserErasure) error
+}
+
+// UserErasure describe la anonimización de una cuenta
+type UserErasure struct {
+	UserID int32
+	// Datos que reemplazan a los personales; Password no debe coincidir con ninguna contraseña
+	UserName string
+	Email    string
+	Password string
+	// Los comentarios publicados por UserID pasan a firmarse como CommentAuthor
+	CommentAuthor string
+	// Audit se guarda con la transacción; Details se completa con los comentarios reescritos
+	Audit *AuditEntry
+}
+
+type UserComment struct {
+	ID        int       `json:"id"`
+	ProductID int       `json:"product_id"`
+	Comment   string    `json:"comment"`
+	Rating    int       `json:"rating"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserDataExport reúne todos los datos que se guardan de un usuario
+type UserDataExport struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Profile     PublicUser     `json:"profile"`
+	Roles       []Role         `json:"roles"`
+	Comments    []UserComment  `json:"comments"`
+	Sessions    []Session      `json:"sessions"`
+	ApiKeys     []ApiKey       `json:"api_keys"`
+	TwoFactor   bool           `json:"two_factor_enabled"`
+	Identities  []UserIdentity `json:"identities"`
+	AuditLog    []AuditEntry   `json:"audit_log"`
+}
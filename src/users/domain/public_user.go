@@ -0,0 +1,70 @@
+package domain
+
+import "fmt"
+
+// PublicUser es la representación de un usuario que devuelven las respuestas HTTP.
+// No tiene campo de contraseña, por lo que el hash nunca puede filtrarse.
+type PublicUser struct {
+	ID        int32  `json:"id"`
+	UserName  string `json:"username"`
+	Email     string `json:"email"`
+	Estado    bool   `json:"estado"`
+	CreatedAt string `json:"created_at"`
+
+	EmailVerified      bool   `json:"email_verified"`
+	DeactivationReason string `json:"deactivation_reason,omitempty"`
+	DeactivatedAt      string `json:"deactivated_at,omitempty"`
+
+	DisplayName string `json:"display_name,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
+	// Avatar contiene la URL de cada tamaño, por ejemplo "128": "/uploads/avatars/7/ab12-128.png"
+	Avatar map[string]string `json:"avatar,omitempty"`
+}
+
+func (u *User) Public() PublicUser {
+	return PublicUser{
+		ID:                 u.ID,
+		UserName:           u.UserName,
+		Email:              u.Email,
+		Estado:             u.Estado,
+		CreatedAt:          u.CreatedAt,
+		EmailVerified:      u.EmailVerified,
+		DeactivationReason: u.DeactivationReason,
+		DeactivatedAt:      u.DeactivatedAt,
+		DisplayName:        u.DisplayName,
+		Phone:              u.Phone,
+		Locale:             u.Locale,
+		Timezone:           u.Timezone,
+		Avatar:             AvatarURLs(u.AvatarURL),
+	}
+}
+
+// Tamaños, en píxeles, en los que se guarda cada avatar
+var AvatarSizes = []int{64, 128, 256}
+
+// AvatarObject devuelve la clave (o URL) de un tamaño del avatar a partir de su base
+func AvatarObject(base string, size int) string {
+	return fmt.Sprintf("%s-%d.png", base, size)
+}
+
+// AvatarURLs devuelve las URLs de todos los tamaños; nil si el usuario no tiene avatar
+func AvatarURLs(baseURL string) map[string]string {
+	if baseURL == "" {
+		return nil
+	}
+	urls := make(map[string]string, len(AvatarSizes))
+	for _, size := range AvatarSizes {
+		urls[fmt.Sprint(size)] = AvatarObject(baseURL, size)
+	}
+	return urls
+}
+
+func PublicUsers(users []User) []PublicUser {
+	public := make([]PublicUser, 0, len(users))
+	for i := range users {
+		public = append(public, users[i].Public())
+	}
+	return public
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// IRefreshToken define la persistencia de los refresh tokens (solo se guarda su hash)
+type IRefreshToken interface {
+	Save(token *RefreshToken) error
+	GetByHash(tokenHash string) (*RefreshToken, error)
+	// MarkRotated marca el token como usado; devuelve false si ya estaba rotado o revocado
+	MarkRotated(id int64) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID int32) error
+}
+
+type RefreshToken struct {
+	ID        int64
+	UserID    int32
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
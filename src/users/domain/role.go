@@ -0,0 +1,32 @@
+package domain
+
+import "errors"
+
+var ErrRoleNotFound = errors.New("rol no encontrado")
+
+// Roles predefinidos
+const (
+	RoleAdmin         = "admin"
+	RoleCatalogEditor = "catalog-editor"
+	RoleModerator     = "moderator"
+	RoleCustomer      = "customer"
+)
+
+// Permisos usados por las rutas de cada módulo
+const (
+	PermissionUsersRead        = "users:read"
+	PermissionUsersWrite       = "users:write"
+	PermissionRolesManage      = "roles:manage"
+	PermissionUsersImpersonate = "users:impersonate"
+	PermissionProductsWrite    = "products:write"
+	PermissionCategoriesWrite  = "categories:write"
+	PermissionCommentsWrite    = "comments:write"
+	PermissionCommentsModerate = "comments:moderate"
+)
+
+type Role struct {
+	ID          int32    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
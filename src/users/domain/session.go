@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("sesión no encontrada")
+
+// ISession define la persistencia de las sesiones creadas al iniciar sesión.
+// El ID de la sesión coincide con la familia de sus refresh tokens.
+type ISession interface {
+	Create(session *Session) error
+	GetByID(id string) (*Session, error)
+	// ListActiveByUser devuelve las sesiones no revocadas con actividad posterior a since
+	ListActiveByUser(userID int32, since time.Time) ([]Session, error)
+	// ListByUser devuelve todas las sesiones del usuario, incluidas las revocadas
+	ListByUser(userID int32) ([]Session, error)
+	Touch(id string, ip string) error
+	// Revoke revoca la sesión; devuelve false si no existe o ya estaba revocada
+	Revoke(id string) (bool, error)
+	RevokeAllForUser(userID int32) error
+}
+
+// IConnectionHub cierra las conexiones en tiempo real asociadas a usuarios o sesiones
+type IConnectionHub interface {
+	DisconnectUser(userID int32)
+	DisconnectSession(sessionID string)
+}
+
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     int32      `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Current    bool       `json:"current"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ITwoFactor define la persistencia de la configuración TOTP y los códigos de recuperación
+type ITwoFactor interface {
+	GetByUserID(userID int32) (*TwoFactor, error)
+	// SaveSecret guarda un secreto pendiente de confirmar, reemplazando cualquier inscripción previa
+	SaveSecret(userID int32, secret string) error
+	Enable(userID int32, recoveryCodeHashes []string) error
+	Disable(userID int32) error
+	// UseStep registra el último paso TOTP usado; devuelve false si ya se usó (repetición)
+	UseStep(userID int32, step int64) (bool, error)
+	// UseRecoveryCode consume un código de recuperación; devuelve false si no existe o ya se usó
+	UseRecoveryCode(userID int32, codeHash string) (bool, error)
+}
+
+type TwoFactor struct {
+	UserID      int32
+	Secret      string
+	Enabled     bool
+	LastStep    int64
+	ConfirmedAt *time.Time
+}
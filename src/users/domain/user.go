@@ -1,21 +1,80 @@
 package domain
 
+import "errors"
+
+var (
+	ErrUserNotFound   = errors.New("usuario no encontrado")
+	ErrUserNameTaken  = errors.New("el nombre de usuario ya está en uso")
+	ErrEmailTaken     = errors.New("el email ya está en uso")
+	ErrUserConflict   = errors.New("ya existe un usuario con esos datos")
+	ErrUserAnonymized = errors.New("la cuenta fue eliminada y no puede reactivarse")
+)
+
 type IUser interface {
-	SaveUser(userName string, email string, password string, estado bool) error
-	DeleteUser(id int32) error
-	UpdateUser(id int32, userName string, email string, password string) error
-	GetAll() ([]User, error)
+	// SaveUser crea el usuario con su rol inicial de forma atómica y devuelve su ID;
+	// con deactivation la cuenta se crea inactiva registrando el motivo y quién lo decidió
+	SaveUser(userName string, email string, password string, role string, deactivation *UserDeactivation) (int32, error)
+	UpdateUser(id int32, changes UserChanges) error
+	// FindUsers devuelve una página de usuarios sin el hash de la contraseña
+	FindUsers(query UserQuery) ([]User, error)
+	// CountUsers cuenta los usuarios que cumplen los filtros, sin tener en cuenta la página
+	CountUsers(query UserQuery) (int, error)
+	GetByID(id int32) (*User, error)
+	GetByEmail(email string) (*User, error)
 	GetUserByCredentials(userName string) (*User, error)
-	ToggleUserStatus(id int32) (bool, error)
+	SetUserStatus(id int32, estado bool, reason string, actorID int32) (bool, error)
+	MarkEmailVerified(id int32) error
+
+	// Roles y permisos
+	ListRoles() ([]Role, error)
+	GetUserRoles(userID int32) ([]Role, error)
+	GetUserPermissions(userID int32) ([]string, error)
+	AssignRole(userID int32, roleName string) error
+	RevokeRole(userID int32, roleName string) error
+}
+
+// UserDeactivation es el motivo y el actor de una cuenta creada inactiva
+type UserDeactivation struct {
+	Reason  string
+	ActorID int32
 }
 
 type User struct {
 	ID        int32  `json:"id"`
 	UserName  string `json:"username"`
 	Email     string `json:"email"`
-	Password  string `json:"password,omitempty"`
+	Password  string `json:"-"`
 	Estado    bool   `json:"estado"`
 	CreatedAt string `json:"created_at"`
+
+	EmailVerified      bool   `json:"email_verified"`
+	DeactivationReason string `json:"deactivation_reason,omitempty"`
+	DeactivatedAt      string `json:"deactivated_at,omitempty"`
+
+	// Perfil
+	DisplayName string `json:"display_name,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
+	// AvatarKey es la clave base en el almacén de archivos; AvatarURL su dirección pública
+	AvatarKey string `json:"-"`
+	AvatarURL string `json:"-"`
+}
+
+// UserChanges contiene los campos a actualizar; los nil se dejan sin cambios
+// y un texto vacío borra los campos opcionales del perfil.
+// Password debe llegar ya hasheado. Cambiar Email deja el email sin verificar.
+type UserChanges struct {
+	UserName *string
+	Email    *string
+	Password *string
+
+	DisplayName *string
+	Phone       *string
+	Locale      *string
+	Timezone    *string
+	AvatarKey   *string
+	AvatarURL   *string
 }
 
 func NewUser(userName string, email string, password string, estado bool) *User {
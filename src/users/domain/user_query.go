@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Campos por los que se permite ordenar el listado de usuarios
+const (
+	UserSortID        = "id"
+	UserSortUserName  = "username"
+	UserSortEmail     = "email"
+	UserSortCreatedAt = "created_at"
+)
+
+// UserQuery describe los filtros, el orden y la página de un listado de usuarios.
+// Si After está presente se usa paginación por cursor y Offset se ignora.
+type UserQuery struct {
+	Search      string
+	Estado      *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SortBy      string
+	SortDesc    bool
+	Limit       int
+	Offset      int
+	After       *UserCursor
+}
+
+// UserCursor es la posición del último usuario devuelto: el valor del campo de orden y su ID
+type UserCursor struct {
+	Value string
+	ID    int32
+}
+
+// SortValue devuelve el valor del campo de orden del usuario, usado para construir el cursor
+func (u *User) SortValue(sortBy string) string {
+	switch sortBy {
+	case UserSortUserName:
+		return u.UserName
+	case UserSortEmail:
+		return u.Email
+	case UserSortCreatedAt:
+		return u.CreatedAt
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,94 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyController struct {
+	createApiKey *application.CreateApiKey
+	listApiKeys  *application.ListApiKeys
+	revokeApiKey *application.RevokeApiKey
+}
+
+func NewApiKeyController(createApiKey *application.CreateApiKey, listApiKeys *application.ListApiKeys, revokeApiKey *application.RevokeApiKey) *ApiKeyController {
+	return &ApiKeyController{createApiKey: createApiKey, listApiKeys: listApiKeys, revokeApiKey: revokeApiKey}
+}
+
+type CreateApiKeyBody struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Create genera una API key para el usuario autenticado
+func (kc *ApiKeyController) Create(c *gin.Context) {
+	var body CreateApiKeyBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	raw, key, err := kc.createApiKey.Execute(user.ID, body.Name, body.Scopes, body.ExpiresAt)
+	if err != nil {
+		kc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key creada. Guárdala ahora, no se volverá a mostrar",
+		"key":     raw,
+		"api_key": key,
+	})
+}
+
+// List devuelve las API keys del usuario autenticado (sin el valor secreto)
+func (kc *ApiKeyController) List(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	keys, err := kc.listApiKeys.Execute(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las API keys", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// Revoke revoca una API key del usuario autenticado
+func (kc *ApiKeyController) Revoke(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de API key inválido"})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	if err := kc.revokeApiKey.Execute(user.ID, id); err != nil {
+		kc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revocada", "id": id})
+}
+
+func (kc *ApiKeyController) respondError(c *gin.Context, err error) {
+	var scopeErr *application.ApiKeyScopeError
+	switch {
+	case errors.Is(err, domain.ErrApiKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrApiKeyNameRequired), errors.Is(err, application.ErrApiKeyExpiry):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.As(err, &scopeErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "scope": scopeErr.Scope})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al gestionar las API keys", "detalles": err.Error()})
+	}
+}
@@ -0,0 +1,120 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"strings"
+)
+
+type MySQLApiKey struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IApiKey = (*MySQLApiKey)(nil)
+
+func NewMySQLApiKey(conn *config.Conn_MySQL) domain.IApiKey {
+	return &MySQLApiKey{conn: conn}
+}
+
+const apiKeyColumns = "id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, last_used_ip, revoked_at, created_at"
+
+func scanApiKey(row rowScanner) (*domain.ApiKey, error) {
+	var key domain.ApiKey
+	var scopes, createdAt string
+	var expiresAt, lastUsedAt, lastUsedIP, revokedAt sql.NullString
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &expiresAt, &lastUsedAt, &lastUsedIP, &revokedAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los scopes se guardan separados por comas
+	key.Scopes = []string{}
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+	key.ExpiresAt = parseNullDateTime(expiresAt)
+	key.LastUsedAt = parseNullDateTime(lastUsedAt)
+	key.LastUsedIP = lastUsedIP.String
+	key.RevokedAt = parseNullDateTime(revokedAt)
+	key.CreatedAt = parseDateTime(createdAt)
+	return &key, nil
+}
+
+func (mysql *MySQLApiKey) Save(key *domain.ApiKey) error {
+	var expiresAt interface{}
+	if key.ExpiresAt != nil {
+		expiresAt = key.ExpiresAt.UTC().Format(dateTimeLayout)
+	}
+
+	query := "INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, UTC_TIMESTAMP())"
+	result, err := mysql.conn.ExecutePreparedQuery(query, key.UserID, key.Name, key.Prefix, key.KeyHash, strings.Join(key.Scopes, ","), expiresAt)
+	if err != nil {
+		return fmt.Errorf("error al guardar la API key: %v", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		key.ID = id
+	}
+	return nil
+}
+
+func (mysql *MySQLApiKey) GetByHash(keyHash string) (*domain.ApiKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = ?"
+	row, err := mysql.conn.FetchRow(query, keyHash)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	key, err := scanApiKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear la API key: %v", err)
+	}
+	return key, nil
+}
+
+func (mysql *MySQLApiKey) ListByUser(userID int32) ([]domain.ApiKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE user_id = ? ORDER BY created_at DESC"
+	rows, err := mysql.conn.FetchRows(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []domain.ApiKey{}
+	for rows.Next() {
+		key, err := scanApiKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear la API key: %v", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre las API keys: %v", err)
+	}
+	return keys, nil
+}
+
+func (mysql *MySQLApiKey) Revoke(userID int32, id int64) (bool, error) {
+	query := "UPDATE api_keys SET revoked_at = UTC_TIMESTAMP() WHERE id = ? AND user_id = ? AND revoked_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("error al revocar la API key: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (mysql *MySQLApiKey) TouchLastUsed(id int64, ip string) error {
+	query := "UPDATE api_keys SET last_used_at = UTC_TIMESTAMP(), last_used_ip = ? WHERE id = ?"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, ip, id); err != nil {
+		return fmt.Errorf("error al registrar el uso de la API key: %v", err)
+	}
+	return nil
+}
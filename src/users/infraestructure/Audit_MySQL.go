@@ -0,0 +1,71 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+)
+
+type MySQLAuditLog struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IAuditLog = (*MySQLAuditLog)(nil)
+
+func NewMySQLAuditLog(conn *config.Conn_MySQL) domain.IAuditLog {
+	return &MySQLAuditLog{conn: conn}
+}
+
+func (mysql *MySQLAuditLog) Record(entry *domain.AuditEntry) error {
+	return recordAudit(mysql.conn.DB, entry)
+}
+
+// RecordTx registra la entrada dentro de la transacción tx, junto con la operación auditada
+func (mysql *MySQLAuditLog) RecordTx(tx *sql.Tx, entry *domain.AuditEntry) error {
+	return recordAudit(tx, entry)
+}
+
+func recordAudit(db sqlExecutor, entry *domain.AuditEntry) error {
+	query := "INSERT INTO audit_log (actor_id, target_user_id, action, details, ip, created_at) VALUES (?, ?, ?, ?, ?, UTC_TIMESTAMP())"
+	result, err := db.Exec(query, entry.ActorID, entry.TargetUserID, entry.Action, entry.Details, entry.IP)
+	if err != nil {
+		return fmt.Errorf("error al registrar la auditoría: %v", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		entry.ID = id
+	}
+	log.Printf("[Audit] - %s: actor %d, usuario %d", entry.Action, entry.ActorID, entry.TargetUserID)
+	return nil
+}
+
+func (mysql *MySQLAuditLog) ListByUser(userID int32) ([]domain.AuditEntry, error) {
+	query := `SELECT id, actor_id, target_user_id, action, details, ip, created_at
+		FROM audit_log WHERE actor_id = ? OR target_user_id = ? ORDER BY created_at DESC, id DESC`
+	rows, err := mysql.conn.FetchRows(query, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var details, ip sql.NullString
+		var createdAt string
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.TargetUserID, &entry.Action, &details, &ip, &createdAt); err != nil {
+			return nil, fmt.Errorf("error al escanear la auditoría: %v", err)
+		}
+		entry.Details = details.String
+		entry.IP = ip.String
+		entry.CreatedAt = parseDateTime(createdAt)
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre la auditoría: %v", err)
+	}
+	return entries, nil
+}
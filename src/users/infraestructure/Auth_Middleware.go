@@ -0,0 +1,153 @@
+package infraestructure
+
+import (
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authenticator resuelve el usuario de un bearer token (access token o API key)
+type authenticator struct {
+	tokens   *application.TokenService
+	apiKeys  *application.ApiKeyAuthenticator
+	sessions *application.SessionService
+	repo     domain.IUser
+	audit    *application.ImpersonationAudit
+}
+
+// NewAuthMiddleware verifica el bearer token del encabezado Authorization (access token o API key)
+// y deja el domain.User autenticado y sus permisos en el contexto
+func NewAuthMiddleware(tokens *application.TokenService, apiKeys *application.ApiKeyAuthenticator, sessions *application.SessionService, repo domain.IUser, audit *application.ImpersonationAudit) gin.HandlerFunc {
+	auth := &authenticator{tokens: tokens, apiKeys: apiKeys, sessions: sessions, repo: repo, audit: audit}
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !found || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso requerido"})
+			return
+		}
+
+		if auth.authenticate(c, token) {
+			c.Next()
+			auth.recordImpersonation(c)
+		}
+	}
+}
+
+// NewWebSocketAuthMiddleware autentica la conexión WebSocket si trae token (encabezado o ?token=,
+// porque los navegadores no permiten encabezados en el handshake); sin token la conexión es anónima
+func NewWebSocketAuthMiddleware(tokens *application.TokenService, apiKeys *application.ApiKeyAuthenticator, sessions *application.SessionService, repo domain.IUser, audit *application.ImpersonationAudit) gin.HandlerFunc {
+	auth := &authenticator{tokens: tokens, apiKeys: apiKeys, sessions: sessions, repo: repo, audit: audit}
+	return func(c *gin.Context) {
+		token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if token == "" {
+			token = c.Query("token")
+		}
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if auth.authenticate(c, token) {
+			c.Next()
+			auth.recordImpersonation(c)
+		}
+	}
+}
+
+// authenticate valida el token y guarda el usuario, sus permisos y la sesión en el contexto.
+// Si falla aborta la petición y devuelve false.
+func (a *authenticator) authenticate(c *gin.Context, token string) bool {
+	var userID int32
+	var scopes []string
+	var sessionID string
+	var impersonatorID int32
+	method := middleware.AuthMethodSession
+	if application.IsApiKey(token) {
+		key, err := a.apiKeys.Authenticate(token, c.ClientIP())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key inválida", "detalles": err.Error()})
+			return false
+		}
+		userID = key.UserID
+		scopes = key.Scopes
+		method = middleware.AuthMethodApiKey
+	} else {
+		claims, err := a.tokens.Parse(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso inválido", "detalles": err.Error()})
+			return false
+		}
+		// Un token de suplantación pertenece a la sesión del administrador, no a la del usuario
+		sessionOwner := claims.UserID
+		if claims.ImpersonatorID != 0 {
+			sessionOwner = claims.ImpersonatorID
+			impersonatorID = claims.ImpersonatorID
+			method = middleware.AuthMethodImpersonation
+		}
+		// El access token deja de valer en cuanto se cierra su sesión
+		if _, err := a.sessions.Validate(claims.SessionID, sessionOwner, c.ClientIP()); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Sesión inválida", "detalles": err.Error()})
+			return false
+		}
+		userID = claims.UserID
+		sessionID = claims.SessionID
+	}
+
+	// El administrador pierde la suplantación en cuanto pierde el permiso
+	if impersonatorID != 0 {
+		impersonatorPermissions, err := a.repo.GetUserPermissions(impersonatorID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los permisos", "detalles": err.Error()})
+			return false
+		}
+		if !slices.Contains(impersonatorPermissions, domain.PermissionUsersImpersonate) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permiso insuficiente", "permission": domain.PermissionUsersImpersonate})
+			return false
+		}
+	}
+
+	// Cargar el usuario para rechazar cuentas desactivadas o eliminadas después de emitir el token
+	user, err := a.repo.GetByID(userID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso inválido", "detalles": err.Error()})
+		return false
+	}
+	if !user.Estado {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": application.ErrAccountDeactivated.Message, "code": application.ErrAccountDeactivated.Code})
+		return false
+	}
+	user.Password = ""
+
+	permissions, err := a.repo.GetUserPermissions(userID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los permisos", "detalles": err.Error()})
+		return false
+	}
+
+	middleware.SetCurrentUser(c, user)
+	// Una API key nunca tiene más permisos que su dueño
+	middleware.SetPermissions(c, application.ScopePermissions(permissions, scopes))
+	middleware.SetAuthMethod(c, method)
+	middleware.SetSessionID(c, sessionID)
+	if impersonatorID != 0 {
+		middleware.SetImpersonator(c, impersonatorID)
+	}
+	return true
+}
+
+// recordImpersonation anota en el log y en la auditoría la petición hecha con un token de suplantación
+func (a *authenticator) recordImpersonation(c *gin.Context) {
+	impersonatorID, ok := middleware.ImpersonatorID(c)
+	if !ok {
+		return
+	}
+	user, _ := middleware.CurrentUser(c)
+	a.audit.Record(impersonatorID, user.ID, c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.ClientIP())
+}
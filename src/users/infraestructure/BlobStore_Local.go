@@ -0,0 +1,90 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalBlobStore guarda los archivos en un directorio local que el servidor publica como ruta estática
+type LocalBlobStore struct {
+	dir     string
+	urlPath string
+}
+
+var _ domain.IBlobStore = (*LocalBlobStore)(nil)
+
+func NewLocalBlobStore(dir string, urlPath string) *LocalBlobStore {
+	return &LocalBlobStore{dir: dir, urlPath: "/" + strings.Trim(urlPath, "/")}
+}
+
+// Dir es el directorio donde se guardan los archivos
+func (s *LocalBlobStore) Dir() string {
+	return s.dir
+}
+
+// URLPath es la ruta pública bajo la que se sirven los archivos
+func (s *LocalBlobStore) URLPath() string {
+	return s.urlPath
+}
+
+// path resuelve la clave dentro del directorio; rechaza claves que intenten salir de él
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", fmt.Errorf("clave de archivo inválida: %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
+
+func (s *LocalBlobStore) Put(key string, contentType string, content io.Reader) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("error al crear el directorio del archivo: %v", err)
+	}
+
+	// Se escribe en un temporal y se renombra para que nunca se sirva un archivo a medias
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error al crear el archivo: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error al guardar el archivo: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error al guardar el archivo: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("error al guardar el archivo: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("error al guardar el archivo: %v", err)
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) Delete(key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error al borrar el archivo: %v", err)
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) URL(key string) string {
+	return s.urlPath + "/" + key
+}
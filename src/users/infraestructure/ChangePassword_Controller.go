@@ -0,0 +1,43 @@
+package infraestructure
+
+import (
+	"expresApi/src/users/application"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChangePasswordController struct {
+	useCase *application.ChangePassword
+}
+
+func NewChangePasswordController(useCase *application.ChangePassword) *ChangePasswordController {
+	return &ChangePasswordController{useCase: useCase}
+}
+
+type ChangePasswordRequestBody struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+func (cp_c *ChangePasswordController) Execute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var body ChangePasswordRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	if err := cp_c.useCase.Execute(int32(id), body.CurrentPassword, body.NewPassword); err != nil {
+		respondUserError(c, err, "Error al cambiar la contraseña")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contraseña actualizada correctamente"})
+}
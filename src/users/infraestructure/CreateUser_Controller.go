@@ -33,7 +33,7 @@ func (cu_c *CreateUserController) Execute(c *gin.Context) {
 
 	err := cu_c.useCase.Execute(body.UserName, body.Email, body.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al agregar el usuario", "detalles": err.Error()})
+		respondUserError(c, err, "Error al agregar el usuario")
 		return
 	}
 
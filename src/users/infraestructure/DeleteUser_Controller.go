@@ -1,8 +1,8 @@
 package infraestructure
 
 import (
+	"expresApi/src/config/middleware"
 	"expresApi/src/users/application"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -17,19 +17,21 @@ func NewDeleteUserController(useCase *application.DeleteUser) *DeleteUserControl
 	return &DeleteUserController{useCase: useCase}
 }
 
+// Execute anonimiza la cuenta del usuario (derecho al olvido)
 func (du_c *DeleteUserController) Execute(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de producto inválido"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
 		return
 	}
 
-	err = du_c.useCase.Execute(int32(id))
+	actor, _ := middleware.CurrentUser(c)
+	err = du_c.useCase.Execute(actor.ID, c.ClientIP(), int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Error al eliminar el usuario: %v", err)})
+		respondUserError(c, err, "Error al eliminar el usuario")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Usuario eliminado correctamente"})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario eliminado correctamente", "user_id": id, "anonymized": true})
+}
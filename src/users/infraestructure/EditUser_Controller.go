@@ -1,7 +1,9 @@
 package infraestructure
 
 import (
+	"expresApi/src/config/middleware"
 	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
 	"net/http"
 	"strconv"
 
@@ -9,11 +11,12 @@ import (
 )
 
 type EditUserController struct {
-	useCase *application.EditUser
+	useCase      *application.EditUser
+	patchUseCase *application.PatchUser
 }
 
-func NewEditUserController(useCase *application.EditUser) *EditUserController {
-	return &EditUserController{useCase: useCase}
+func NewEditUserController(useCase *application.EditUser, patchUseCase *application.PatchUser) *EditUserController {
+	return &EditUserController{useCase: useCase, patchUseCase: patchUseCase}
 }
 
 func (eu_c *EditUserController) Execute(c *gin.Context) {
@@ -25,21 +28,58 @@ func (eu_c *EditUserController) Execute(c *gin.Context) {
 	}
 
 	var body struct {
-		ID          int32 `json:"id"`
-	    UserName	string `json:"userName"`
-	    Email 		string `json:"email"`
-	    Password	string `json:"password"`
+		ID       int32  `json:"id"`
+		UserName string `json:"userName"`
+		Email    string `json:"email"`
+		Password string `json:"password"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer los datos"})
 		return
 	}
 
+	// Solo un administrador puede fijar la contraseña sin conocer la actual
+	if body.Password != "" && !middleware.HasPermission(c, domain.PermissionUsersWrite) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Usa el endpoint de cambio de contraseña para modificar tu contraseña"})
+		return
+	}
+
 	err = eu_c.useCase.Execute(int32(id), body.UserName, body.Email, body.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el usuario", "detalles": err.Error()})
+		respondUserError(c, err, "Error al actualizar el usuario")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Usuario actualizado correctamente"})
-}
\ No newline at end of file
+}
+
+// Patch actualiza solo los campos presentes en el cuerpo
+func (eu_c *EditUserController) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var body struct {
+		UserName *string `json:"userName"`
+		Email    *string `json:"email"`
+		Password *string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer los datos", "detalles": err.Error()})
+		return
+	}
+	if body.Password != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Usa el endpoint de cambio de contraseña para modificar la contraseña"})
+		return
+	}
+
+	user, err := eu_c.patchUseCase.Execute(int32(id), body.UserName, body.Email)
+	if err != nil {
+		respondUserError(c, err, "Error al actualizar el usuario")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario actualizado correctamente", "user": user.Public()})
+}
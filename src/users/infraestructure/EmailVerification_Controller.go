@@ -0,0 +1,68 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/users/application"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailVerificationController struct {
+	verifyEmail        *application.VerifyEmail
+	resendVerification *application.ResendVerification
+}
+
+func NewEmailVerificationController(verifyEmail *application.VerifyEmail, resendVerification *application.ResendVerification) *EmailVerificationController {
+	return &EmailVerificationController{verifyEmail: verifyEmail, resendVerification: resendVerification}
+}
+
+type ResendVerificationRequestBody struct {
+	Email string `json:"email"`
+}
+
+// Verify confirma el email con el token recibido por correo
+func (vc *EmailVerificationController) Verify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token es requerido"})
+		return
+	}
+
+	if err := vc.verifyEmail.Execute(token); err != nil {
+		if errors.Is(err, application.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar el email", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verificado correctamente"})
+}
+
+// Resend reenvía el enlace de verificación
+func (vc *EmailVerificationController) Resend(c *gin.Context) {
+	var body ResendVerificationRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	if err := vc.resendVerification.Execute(body.Email, c.ClientIP()); err != nil {
+		var limitErr *application.ResendLimitError
+		switch {
+		case errors.As(err, &limitErr):
+			c.Header("Retry-After", strconv.Itoa(int(limitErr.RetryAfter.Seconds())))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": limitErr.Error()})
+		case errors.Is(err, application.ErrEmailRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al reenviar la verificación"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Si la cuenta está pendiente de verificación recibirás un nuevo enlace"})
+}
@@ -0,0 +1,91 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+)
+
+type MySQLEmailVerification struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IEmailVerification = (*MySQLEmailVerification)(nil)
+
+func NewMySQLEmailVerification(conn *config.Conn_MySQL) domain.IEmailVerification {
+	return &MySQLEmailVerification{conn: conn}
+}
+
+func (mysql *MySQLEmailVerification) Save(token *domain.EmailVerificationToken) error {
+	query := "INSERT INTO email_verification_tokens (user_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, UTC_TIMESTAMP())"
+	result, err := mysql.conn.ExecutePreparedQuery(query, token.UserID, token.TokenHash, token.ExpiresAt.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error al guardar el token de verificación: %v", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		token.ID = id
+	}
+	return nil
+}
+
+func (mysql *MySQLEmailVerification) GetByHash(tokenHash string) (*domain.EmailVerificationToken, error) {
+	query := "SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM email_verification_tokens WHERE token_hash = ?"
+	row, err := mysql.conn.FetchRow(query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var token domain.EmailVerificationToken
+	var expiresAt, createdAt string
+	var usedAt sql.NullString
+	err = row.Scan(&token.ID, &token.UserID, &token.TokenHash, &expiresAt, &usedAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear el token de verificación: %v", err)
+	}
+
+	token.ExpiresAt = parseDateTime(expiresAt)
+	token.CreatedAt = parseDateTime(createdAt)
+	token.UsedAt = parseNullDateTime(usedAt)
+	return &token, nil
+}
+
+func (mysql *MySQLEmailVerification) Redeem(id int64, userID int32) (bool, error) {
+	tx, err := mysql.conn.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE email_verification_tokens SET used_at = UTC_TIMESTAMP() WHERE id = ? AND user_id = ? AND used_at IS NULL"
+	result, err := tx.Exec(query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("error al marcar el token como usado: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 1 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec("UPDATE user SET email_verified_at = UTC_TIMESTAMP() WHERE id = ? AND email_verified_at IS NULL", userID); err != nil {
+		return false, fmt.Errorf("error al verificar el email: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+	log.Printf("[MySQL] - Email verificado para el usuario ID: %d", userID)
+	return true, nil
+}
+
+func (mysql *MySQLEmailVerification) InvalidateForUser(userID int32) error {
+	query := "UPDATE email_verification_tokens SET used_at = UTC_TIMESTAMP() WHERE user_id = ? AND used_at IS NULL"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, userID); err != nil {
+		return fmt.Errorf("error al invalidar los tokens de verificación: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package infraestructure
+
+import (
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportUserDataController struct {
+	useCase *application.ExportUserData
+}
+
+func NewExportUserDataController(useCase *application.ExportUserData) *ExportUserDataController {
+	return &ExportUserDataController{useCase: useCase}
+}
+
+// Execute descarga un archivo JSON con todos los datos del usuario
+func (ed_c *ExportUserDataController) Execute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	actor, _ := middleware.CurrentUser(c)
+	export, err := ed_c.useCase.Execute(actor.ID, c.ClientIP(), int32(id))
+	if err != nil {
+		respondUserError(c, err, "Error al exportar los datos del usuario")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=user-%d-data.json", id))
+	c.JSON(http.StatusOK, export)
+}
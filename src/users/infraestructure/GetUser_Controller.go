@@ -0,0 +1,46 @@
+package infraestructure
+
+import (
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetUserController struct {
+	useCase *application.GetUser
+}
+
+func NewGetUserController(useCase *application.GetUser) *GetUserController {
+	return &GetUserController{useCase: useCase}
+}
+
+// Execute devuelve un usuario por su ID
+func (gu_c *GetUserController) Execute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	user, err := gu_c.useCase.Execute(int32(id))
+	if err != nil {
+		respondUserError(c, err, "Error al obtener el usuario")
+		return
+	}
+
+	c.JSON(http.StatusOK, user.Public())
+}
+
+// Me devuelve el usuario autenticado
+func (gu_c *GetUserController) Me(c *gin.Context) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso requerido"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user.Public())
+}
@@ -0,0 +1,148 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+)
+
+type MySQLUserIdentity struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IUserIdentity = (*MySQLUserIdentity)(nil)
+
+func NewMySQLUserIdentity(conn *config.Conn_MySQL) domain.IUserIdentity {
+	return &MySQLUserIdentity{conn: conn}
+}
+
+const userIdentityColumns = "id, user_id, issuer, subject, email, created_at, last_login_at"
+
+func scanUserIdentity(row rowScanner) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	var createdAt string
+	var lastLoginAt sql.NullString
+	err := row.Scan(&identity.ID, &identity.UserID, &identity.Issuer, &identity.Subject, &identity.Email, &createdAt, &lastLoginAt)
+	if err != nil {
+		return nil, err
+	}
+
+	identity.CreatedAt = parseDateTime(createdAt)
+	identity.LastLoginAt = parseNullDateTime(lastLoginAt)
+	return &identity, nil
+}
+
+func (mysql *MySQLUserIdentity) Save(identity *domain.UserIdentity) error {
+	query := "INSERT INTO user_identities (user_id, issuer, subject, email, created_at) VALUES (?, ?, ?, ?, UTC_TIMESTAMP())"
+	result, err := mysql.conn.ExecutePreparedQuery(query, identity.UserID, identity.Issuer, identity.Subject, identity.Email)
+	if err != nil {
+		return fmt.Errorf("error al guardar la identidad externa: %v", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		identity.ID = id
+	}
+	return nil
+}
+
+func (mysql *MySQLUserIdentity) GetBySubject(issuer string, subject string) (*domain.UserIdentity, error) {
+	query := "SELECT " + userIdentityColumns + " FROM user_identities WHERE issuer = ? AND subject = ?"
+	row, err := mysql.conn.FetchRow(query, issuer, subject)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	identity, err := scanUserIdentity(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear la identidad externa: %v", err)
+	}
+	return identity, nil
+}
+
+func (mysql *MySQLUserIdentity) ListByUser(userID int32) ([]domain.UserIdentity, error) {
+	query := "SELECT " + userIdentityColumns + " FROM user_identities WHERE user_id = ? ORDER BY created_at"
+	rows, err := mysql.conn.FetchRows(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []domain.UserIdentity{}
+	for rows.Next() {
+		identity, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear la identidad externa: %v", err)
+		}
+		identities = append(identities, *identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre las identidades externas: %v", err)
+	}
+	return identities, nil
+}
+
+func (mysql *MySQLUserIdentity) Delete(userID int32, id int64) (bool, error) {
+	query := "DELETE FROM user_identities WHERE id = ? AND user_id = ?"
+	result, err := mysql.conn.ExecutePreparedQuery(query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("error al desvincular la identidad externa: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (mysql *MySQLUserIdentity) TouchLastLogin(id int64) error {
+	query := "UPDATE user_identities SET last_login_at = UTC_TIMESTAMP() WHERE id = ?"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, id); err != nil {
+		return fmt.Errorf("error al registrar el inicio de sesión externo: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQLUserIdentity) SaveAuthRequest(request *domain.OIDCAuthRequest) error {
+	var linkUserID interface{}
+	if request.LinkUserID != 0 {
+		linkUserID = request.LinkUserID
+	}
+
+	query := "INSERT INTO oidc_auth_requests (state_hash, nonce, code_verifier, link_user_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?, UTC_TIMESTAMP())"
+	_, err := mysql.conn.ExecutePreparedQuery(query, request.StateHash, request.Nonce, request.CodeVerifier, linkUserID, request.ExpiresAt.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error al guardar la solicitud de autorización: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQLUserIdentity) ConsumeAuthRequest(stateHash string) (*domain.OIDCAuthRequest, error) {
+	// Marcar primero como usada garantiza que dos callbacks con el mismo state no pasen a la vez
+	query := "UPDATE oidc_auth_requests SET used_at = UTC_TIMESTAMP() WHERE state_hash = ? AND used_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, stateHash)
+	if err != nil {
+		return nil, fmt.Errorf("error al consumir la solicitud de autorización: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 1 {
+		return nil, nil
+	}
+
+	query = "SELECT state_hash, nonce, code_verifier, link_user_id, expires_at FROM oidc_auth_requests WHERE state_hash = ?"
+	row, err := mysql.conn.FetchRow(query, stateHash)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var request domain.OIDCAuthRequest
+	var linkUserID sql.NullInt32
+	var expiresAt string
+	if err := row.Scan(&request.StateHash, &request.Nonce, &request.CodeVerifier, &linkUserID, &expiresAt); err != nil {
+		return nil, fmt.Errorf("error al escanear la solicitud de autorización: %v", err)
+	}
+	request.LinkUserID = linkUserID.Int32
+	request.ExpiresAt = parseDateTime(expiresAt)
+	return &request, nil
+}
@@ -0,0 +1,68 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationController struct {
+	useCase *application.ImpersonateUser
+}
+
+func NewImpersonationController(useCase *application.ImpersonateUser) *ImpersonationController {
+	return &ImpersonationController{useCase: useCase}
+}
+
+type ImpersonateRequestBody struct {
+	Reason string `json:"reason"`
+}
+
+// Execute emite un token para actuar como el usuario indicado (solo administradores)
+func (ic *ImpersonationController) Execute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var body ImpersonateRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	actor, _ := middleware.CurrentUser(c)
+	result, err := ic.useCase.Execute(actor.ID, middleware.CurrentSessionID(c), c.ClientIP(), int32(id), body.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrImpersonateAdmin):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrAccountDeactivated):
+			c.JSON(http.StatusConflict, gin.H{"error": application.ErrAccountDeactivated.Message, "code": application.ErrAccountDeactivated.Code})
+		case errors.Is(err, application.ErrImpersonateSelf), errors.Is(err, application.ErrImpersonateReason),
+			errors.Is(err, application.ErrImpersonateSession):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al suplantar al usuario", "detalles": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Suplantación iniciada",
+		"user":            result.User.Public(),
+		"impersonator_id": result.ImpersonatorID,
+		"access_token":    result.AccessToken,
+		"token_type":      "Bearer",
+		"expires_at":      result.ExpiresAt.Format(time.RFC3339),
+	})
+}
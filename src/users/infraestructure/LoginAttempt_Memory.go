@@ -0,0 +1,96 @@
+package infraestructure
+
+import (
+	"expresApi/src/users/domain"
+	"sync"
+	"time"
+)
+
+// MemoryLoginAttemptStore guarda los intentos de login en memoria (una sola instancia del servidor).
+// Las entradas sin actividad durante ttl se eliminan para que el mapa no crezca sin límite.
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]*domain.LoginAttempt
+	ttl       time.Duration
+	lastSweep time.Time
+}
+
+var _ domain.ILoginAttemptStore = (*MemoryLoginAttemptStore)(nil)
+
+// Cada cuánto se recorre el mapa como máximo para eliminar las entradas caducadas
+const loginAttemptSweepInterval = time.Minute
+
+func NewMemoryLoginAttemptStore(ttl time.Duration) domain.ILoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]*domain.LoginAttempt), ttl: ttl, lastSweep: time.Now()}
+}
+
+func (m *MemoryLoginAttemptStore) Get(key string) (*domain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok || attempt.IsStale(time.Now(), m.ttl) {
+		return nil, nil
+	}
+	snapshot := *attempt
+	return &snapshot, nil
+}
+
+func (m *MemoryLoginAttemptStore) RecordFailure(key string, window time.Duration) (*domain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+	attempt := m.entry(key, now)
+	if now.Sub(attempt.LastFailureAt) > window {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+
+	snapshot := *attempt
+	return &snapshot, nil
+}
+
+func (m *MemoryLoginAttemptStore) Lock(key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt := m.entry(key, time.Now())
+	attempt.Failures = 0
+	attempt.LockCount++
+	attempt.LockedUntil = until
+	return nil
+}
+
+func (m *MemoryLoginAttemptStore) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+	return nil
+}
+
+// entry devuelve la entrada de la clave, empezando de cero si no existe o ya caducó
+func (m *MemoryLoginAttemptStore) entry(key string, now time.Time) *domain.LoginAttempt {
+	attempt, ok := m.attempts[key]
+	if !ok || attempt.IsStale(now, m.ttl) {
+		attempt = &domain.LoginAttempt{Key: key}
+		m.attempts[key] = attempt
+	}
+	return attempt
+}
+
+// sweep elimina las entradas caducadas; se llama con el mutex tomado
+func (m *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < loginAttemptSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, attempt := range m.attempts {
+		if attempt.IsStale(now, m.ttl) {
+			delete(m.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"time"
+)
+
+// MySQLLoginAttemptStore guarda los intentos de login en MySQL (compartido entre instancias)
+type MySQLLoginAttemptStore struct {
+	conn *config.Conn_MySQL
+	ttl  time.Duration
+}
+
+var _ domain.ILoginAttemptStore = (*MySQLLoginAttemptStore)(nil)
+
+func NewMySQLLoginAttemptStore(conn *config.Conn_MySQL, ttl time.Duration) domain.ILoginAttemptStore {
+	return &MySQLLoginAttemptStore{conn: conn, ttl: ttl}
+}
+
+func (mysql *MySQLLoginAttemptStore) Get(key string) (*domain.LoginAttempt, error) {
+	query := `SELECT attempt_key, failures, lock_count, last_failure_at, locked_until
+		FROM login_attempts WHERE attempt_key = ?`
+	row, err := mysql.conn.FetchRow(query, key)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var attempt domain.LoginAttempt
+	var lastFailureAt, lockedUntil sql.NullString
+	err = row.Scan(&attempt.Key, &attempt.Failures, &attempt.LockCount, &lastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear el intento de login: %v", err)
+	}
+
+	if lastFailureAt.Valid {
+		attempt.LastFailureAt = parseDateTime(lastFailureAt.String)
+	}
+	if lockedUntil.Valid {
+		attempt.LockedUntil = parseDateTime(lockedUntil.String)
+	}
+	if attempt.IsStale(time.Now(), mysql.ttl) {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (mysql *MySQLLoginAttemptStore) RecordFailure(key string, window time.Duration) (*domain.LoginAttempt, error) {
+	// lock_count se evalúa primero: MySQL aplica las asignaciones en orden y usa los valores ya actualizados
+	query := `INSERT INTO login_attempts (attempt_key, failures, lock_count, last_failure_at)
+		VALUES (?, 1, 0, UTC_TIMESTAMP())
+		ON DUPLICATE KEY UPDATE
+			lock_count = IF(? > 0 AND GREATEST(COALESCE(last_failure_at, '1970-01-01'), COALESCE(locked_until, '1970-01-01')) < UTC_TIMESTAMP() - INTERVAL ? SECOND, 0, lock_count),
+			failures = IF(last_failure_at IS NULL OR last_failure_at < UTC_TIMESTAMP() - INTERVAL ? SECOND, 1, failures + 1),
+			last_failure_at = UTC_TIMESTAMP()`
+	ttl := int(mysql.ttl.Seconds())
+	if _, err := mysql.conn.ExecutePreparedQuery(query, key, ttl, ttl, int(window.Seconds())); err != nil {
+		return nil, fmt.Errorf("error al registrar el intento fallido: %v", err)
+	}
+
+	return mysql.Get(key)
+}
+
+func (mysql *MySQLLoginAttemptStore) Lock(key string, until time.Time) error {
+	query := `INSERT INTO login_attempts (attempt_key, failures, lock_count, locked_until)
+		VALUES (?, 0, 1, ?)
+		ON DUPLICATE KEY UPDATE failures = 0, lock_count = lock_count + 1, locked_until = VALUES(locked_until)`
+	if _, err := mysql.conn.ExecutePreparedQuery(query, key, until.UTC().Format(dateTimeLayout)); err != nil {
+		return fmt.Errorf("error al bloquear el login: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQLLoginAttemptStore) Reset(key string) error {
+	if _, err := mysql.conn.ExecutePreparedQuery("DELETE FROM login_attempts WHERE attempt_key = ?", key); err != nil {
+		return fmt.Errorf("error al reiniciar los intentos de login: %v", err)
+	}
+	return nil
+}
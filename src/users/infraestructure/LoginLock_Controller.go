@@ -0,0 +1,33 @@
+package infraestructure
+
+import (
+	"expresApi/src/users/application"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginLockController struct {
+	throttle *application.LoginThrottle
+}
+
+func NewLoginLockController(throttle *application.LoginThrottle) *LoginLockController {
+	return &LoginLockController{throttle: throttle}
+}
+
+// Clear elimina el bloqueo de login de un usuario (?username=) y/o una IP (?ip=)
+func (lc *LoginLockController) Clear(c *gin.Context) {
+	userName := c.Query("username")
+	ip := c.Query("ip")
+	if userName == "" && ip == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Indica username o ip"})
+		return
+	}
+
+	if err := lc.throttle.Clear(userName, ip); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el bloqueo", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bloqueo de login eliminado correctamente", "username": userName, "ip": ip})
+}
@@ -1,8 +1,11 @@
 package infraestructure
 
 import (
+	"errors"
 	"expresApi/src/users/application"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,16 +30,62 @@ func (lc *LoginUserController) Execute(c *gin.Context) {
 		return
 	}
 
-	user, err := lc.useCase.Execute(body.UserName, body.Password)
+	result, err := lc.useCase.Execute(body.UserName, body.Password, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciales inválidas"})
+		respondAuthError(c, err)
+		return
+	}
+
+	respondLoginResult(c, result)
+}
+
+// respondLoginResult responde con el usuario y los tokens de una sesión emitida
+func respondLoginResult(c *gin.Context, result *application.LoginResult) {
+	if result.TwoFactorRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"message":              "Se requiere el código de verificación en dos pasos",
+			"two_factor_required":  true,
+			"challenge":            result.Challenge,
+			"challenge_expires_at": result.ChallengeExpiresAt.Format(time.RFC3339),
+		})
 		return
 	}
 
-	// No devolver la contraseña en la respuesta
-	user.Password = ""
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login exitoso",
-		"user":    user,
+		"message":            "Login exitoso",
+		"user":               result.User.Public(),
+		"session_id":         result.SessionID,
+		"access_token":       result.AccessToken,
+		"token_type":         "Bearer",
+		"expires_at":         result.ExpiresAt.Format(time.RFC3339),
+		"refresh_token":      result.RefreshToken,
+		"refresh_expires_at": result.RefreshExpiresAt.Format(time.RFC3339),
 	})
 }
+
+// clientInfo extrae la IP y el User-Agent con los que se registra la sesión
+func clientInfo(c *gin.Context) application.ClientInfo {
+	return application.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// respondAuthError responde con el código legible por máquinas de un AuthError
+func respondAuthError(c *gin.Context, err error) {
+	var lockoutErr *application.LockoutError
+	if errors.As(err, &lockoutErr) {
+		c.Header("Retry-After", strconv.Itoa(int(lockoutErr.RetryAfter.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": lockoutErr.Error(), "code": application.CodeTooManyAttempts})
+		return
+	}
+
+	var authErr *application.AuthError
+	if !errors.As(err, &authErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al iniciar sesión", "detalles": err.Error()})
+		return
+	}
+
+	status := http.StatusUnauthorized
+	if authErr == application.ErrAccountDeactivated || authErr == application.ErrEmailNotVerified {
+		status = http.StatusForbidden
+	}
+	c.JSON(status, gin.H{"error": authErr.Message, "code": authErr.Code})
+}
@@ -0,0 +1,38 @@
+package infraestructure
+
+import (
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LogoutUserController struct {
+	useCase *application.LogoutUser
+}
+
+func NewLogoutUserController(useCase *application.LogoutUser) *LogoutUserController {
+	return &LogoutUserController{useCase: useCase}
+}
+
+func (lc *LogoutUserController) Execute(c *gin.Context) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token de acceso requerido"})
+		return
+	}
+
+	// Durante una suplantación la sesión es del administrador: se cierran las suyas, no las del suplantado
+	ownerID := user.ID
+	if impersonatorID, ok := middleware.ImpersonatorID(c); ok {
+		ownerID = impersonatorID
+	}
+
+	if err := lc.useCase.Execute(ownerID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al cerrar la sesión", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada correctamente"})
+}
@@ -0,0 +1,55 @@
+package infraestructure
+
+import (
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StdoutMailer escribe los correos en el log del servidor (desarrollo local)
+type StdoutMailer struct{}
+
+var _ domain.Mailer = (*StdoutMailer)(nil)
+
+func NewStdoutMailer() domain.Mailer {
+	return &StdoutMailer{}
+}
+
+func (m *StdoutMailer) Send(to string, subject string, body string) error {
+	log.Printf("[Mailer] - Para: %s | Asunto: %s\n%s", to, subject, body)
+	return nil
+}
+
+// FileMailer guarda cada correo como un archivo .eml en un directorio local
+type FileMailer struct {
+	dir string
+}
+
+var _ domain.Mailer = (*FileMailer)(nil)
+
+func NewFileMailer(dir string) domain.Mailer {
+	return &FileMailer{dir: dir}
+}
+
+func (m *FileMailer) Send(to string, subject string, body string) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("error al crear el directorio de correos: %v", err)
+	}
+
+	now := time.Now()
+	safeTo := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(to)
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102T150405.000000000"), safeTo)
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		to, subject, now.Format(time.RFC1123Z), body)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o600); err != nil {
+		return fmt.Errorf("error al guardar el correo: %v", err)
+	}
+
+	log.Printf("[Mailer] - Correo para %s guardado en %s", to, filepath.Join(m.dir, name))
+	return nil
+}
@@ -1,12 +1,15 @@
 package infraestructure
 
 import (
+	"database/sql"
 	"expresApi/src/config"
 	"expresApi/src/users/domain"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
-	"github.com/gin-gonic/gin"
+	mysqlDriver "github.com/go-sql-driver/mysql"
 )
 
 type MySQL struct {
@@ -23,38 +26,188 @@ func NewMySQL() domain.IUser {
 	return &MySQL{conn: conn}
 }
 
-func (mysql *MySQL) SaveUser(userName string, email string, password string, estado bool) error {
-	query := "INSERT INTO user (userName, email, password, estado, created_at) VALUES (?, ?, ?, ?, NOW())"
-	result, err := mysql.conn.ExecutePreparedQuery(query, userName, email, password, estado)
+// Columnas y escaneo comunes a todas las consultas de usuarios
+const userColumns = "id, userName, email, password, estado, created_at, email_verified_at IS NOT NULL, deactivation_reason, deactivated_at, " + userProfileColumns
+
+// Los listados no leen el hash de la contraseña
+const userListColumns = "id, userName, email, '' AS password, estado, created_at, email_verified_at IS NOT NULL, deactivation_reason, deactivated_at, " + userProfileColumns
+
+const userProfileColumns = "display_name, phone, locale, timezone, avatar_key, avatar_url"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var user domain.User
+	var reason, deactivatedAt sql.NullString
+	var displayName, phone, locale, timezone, avatarKey, avatarURL sql.NullString
+	err := row.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Estado, &user.CreatedAt, &user.EmailVerified, &reason, &deactivatedAt,
+		&displayName, &phone, &locale, &timezone, &avatarKey, &avatarURL)
 	if err != nil {
-		return fmt.Errorf("error al ejecutar la consulta: %v", err)
+		return nil, err
 	}
+	user.DeactivationReason = reason.String
+	user.DeactivatedAt = deactivatedAt.String
+	user.DisplayName = displayName.String
+	user.Phone = phone.String
+	user.Locale = locale.String
+	user.Timezone = timezone.String
+	user.AvatarKey = avatarKey.String
+	user.AvatarURL = avatarURL.String
+	return &user, nil
+}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 1 {
-		log.Printf("[MySQL] - Usuario creado correctamente: Username:%s Email:%s Estado:%t", userName, email, estado)
-	} else {
-		log.Println("[MySQL] - No se insertó ninguna fila")
+// isDuplicateKeyError detecta violaciones de índices UNIQUE (error 1062 de MySQL)
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysqlDriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// SaveUser inserta el usuario y le asigna su rol inicial en la misma transacción,
+// para que nunca quede una cuenta sin rol y sin forma de obtener permisos
+func (mysql *MySQL) SaveUser(userName string, email string, password string, role string, deactivation *domain.UserDeactivation) (int32, error) {
+	tx, err := mysql.conn.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error al iniciar la transacción: %v", err)
 	}
-	return nil
+	defer tx.Rollback()
+
+	var roleID int32
+	err = tx.QueryRow("SELECT id FROM roles WHERE name = ?", role).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrRoleNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error al consultar el rol: %v", err)
+	}
+
+	estado := deactivation == nil
+	var reason sql.NullString
+	var deactivatedBy sql.NullInt32
+	if deactivation != nil {
+		reason = sql.NullString{String: deactivation.Reason, Valid: true}
+		deactivatedBy = sql.NullInt32{Int32: deactivation.ActorID, Valid: true}
+	}
+
+	query := `INSERT INTO user (userName, email, password, estado, deactivation_reason, deactivated_at, deactivated_by, created_at)
+		VALUES (?, ?, ?, ?, ?, IF(?, NULL, UTC_TIMESTAMP()), ?, UTC_TIMESTAMP())`
+	result, err := tx.Exec(query, userName, email, password, estado, reason, estado, deactivatedBy)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return 0, domain.ErrUserConflict
+		}
+		return 0, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error al obtener el ID del usuario: %v", err)
+	}
+
+	if _, err := tx.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", id, roleID); err != nil {
+		return 0, fmt.Errorf("error al asignar el rol: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+	log.Printf("[MySQL] - Usuario creado correctamente: Username:%s Email:%s Estado:%t Rol:%s", userName, email, estado, role)
+	return int32(id), nil
+}
+
+// Columnas permitidas para ordenar; el resto de valores nunca llega a la consulta
+var userSortColumns = map[string]string{
+	domain.UserSortID:        "id",
+	domain.UserSortUserName:  "userName",
+	domain.UserSortEmail:     "email",
+	domain.UserSortCreatedAt: "created_at",
+}
+
+// userFilters construye el WHERE común al listado y al conteo de usuarios
+func userFilters(query domain.UserQuery) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		conditions = append(conditions, "(userName LIKE ? OR email LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	if query.Estado != nil {
+		conditions = append(conditions, "estado = ?")
+		args = append(args, *query.Estado)
+	}
+	if query.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.CreatedFrom.UTC().Format(dateTimeLayout))
+	}
+	if query.CreatedTo != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, query.CreatedTo.UTC().Format(dateTimeLayout))
+	}
+	return conditions, args
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
 }
 
-func (mysql *MySQL) GetAll() ([]domain.User, error) {
-	query := "SELECT id, userName, email, password, estado, created_at FROM user"
-	rows, err := mysql.conn.FetchRows(query)
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func (mysql *MySQL) FindUsers(query domain.UserQuery) ([]domain.User, error) {
+	column, ok := userSortColumns[query.SortBy]
+	if !ok {
+		column = "id"
+	}
+	direction, comparator := "ASC", ">"
+	if query.SortDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	conditions, args := userFilters(query)
+	if query.After != nil {
+		// Paginación por cursor (keyset): el ID desempata valores repetidos
+		if column == "id" {
+			conditions = append(conditions, fmt.Sprintf("id %s ?", comparator))
+			args = append(args, query.After.ID)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, comparator, column, comparator))
+			args = append(args, query.After.Value, query.After.Value, query.After.ID)
+		}
+	}
+
+	sqlQuery := "SELECT " + userListColumns + " FROM user" + whereClause(conditions)
+	if column == "id" {
+		sqlQuery += fmt.Sprintf(" ORDER BY id %s", direction)
+	} else {
+		sqlQuery += fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+	}
+	sqlQuery += " LIMIT ?"
+	args = append(args, query.Limit)
+	if query.After == nil && query.Offset > 0 {
+		sqlQuery += " OFFSET ?"
+		args = append(args, query.Offset)
+	}
+
+	rows, err := mysql.conn.FetchRows(sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("Error al ejecutar la consulta SELECT: %v", err)
 	}
 	defer rows.Close()
 
-	var users []domain.User
-
+	users := []domain.User{}
 	for rows.Next() {
-		var user domain.User
-		if err := rows.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Estado, &user.CreatedAt); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, fmt.Errorf("Error al escanear la fila: %v", err)
 		}
-		users = append(users, user)
+		users = append(users, *user)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -63,113 +216,183 @@ func (mysql *MySQL) GetAll() ([]domain.User, error) {
 	return users, nil
 }
 
-func (mysql *MySQL) UpdateUser(id int32, userName string, email string, password string) error {
-	query := "UPDATE user SET userName = ?, email = ?, password = ? WHERE id = ?"
-	result, err := mysql.conn.ExecutePreparedQuery(query, userName, email, password, id)
+func (mysql *MySQL) CountUsers(query domain.UserQuery) (int, error) {
+	conditions, args := userFilters(query)
+	row, err := mysql.conn.FetchRow("SELECT COUNT(*) FROM user"+whereClause(conditions), args...)
 	if err != nil {
-		return fmt.Errorf("Error al ejecutar la consulta: %v", err)
+		return 0, fmt.Errorf("error al ejecutar la consulta: %v", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 1 {
-		log.Printf("[MySQL] - Usuario actualizado correctamente: ID: %d Username:%s Email: %s", id, userName, email)
-	} else {
-		log.Println("[MySQL] - No se actualizó ninguna fila")
+	var total int
+	if err := row.Scan(&total); err != nil {
+		return 0, fmt.Errorf("error al contar los usuarios: %v", err)
 	}
-	return nil
+	return total, nil
 }
 
-func (mysql *MySQL) DeleteUser(id int32) error {
-	query := "DELETE FROM user WHERE id = ?"
-	result, err := mysql.conn.ExecutePreparedQuery(query, id)
+func (mysql *MySQL) GetByID(id int32) (*domain.User, error) {
+	query := "SELECT " + userColumns + " FROM user WHERE id = ?"
+	row, err := mysql.conn.FetchRow(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	user, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
 	if err != nil {
+		return nil, fmt.Errorf("error al escanear el usuario: %v", err)
+	}
+
+	return user, nil
+}
+
+// UpdateUser actualiza solo los campos presentes en changes
+func (mysql *MySQL) UpdateUser(id int32, changes domain.UserChanges) error {
+	setParts := []string{}
+	args := []interface{}{}
+
+	if changes.UserName != nil {
+		setParts = append(setParts, "userName = ?")
+		args = append(args, *changes.UserName)
+	}
+	if changes.Email != nil {
+		// Un email distinto vuelve a quedar pendiente de verificación; va antes de "email = ?"
+		// porque MySQL evalúa las asignaciones en orden con los valores ya actualizados
+		setParts = append(setParts, "email_verified_at = IF(LOWER(email) = LOWER(?), email_verified_at, NULL)", "email = ?")
+		args = append(args, *changes.Email, *changes.Email)
+	}
+	if changes.Password != nil {
+		setParts = append(setParts, "password = ?")
+		args = append(args, *changes.Password)
+	}
+
+	// Los campos del perfil vacíos se guardan como NULL
+	optional := []struct {
+		column string
+		value  *string
+	}{
+		{"display_name", changes.DisplayName},
+		{"phone", changes.Phone},
+		{"locale", changes.Locale},
+		{"timezone", changes.Timezone},
+		{"avatar_key", changes.AvatarKey},
+		{"avatar_url", changes.AvatarURL},
+	}
+	for _, field := range optional {
+		if field.value == nil {
+			continue
+		}
+		setParts = append(setParts, field.column+" = ?")
+		args = append(args, sql.NullString{String: *field.value, Valid: *field.value != ""})
+	}
+
+	if len(setParts) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("UPDATE user SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	args = append(args, id)
+
+	result, err := mysql.conn.ExecutePreparedQuery(query, args...)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return domain.ErrUserConflict
+		}
 		return fmt.Errorf("Error al ejecutar la consulta: %v", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 1 {
-		log.Printf("[MySQL] - Usuario eliminado correctamente: ID: %d", id)
+		log.Printf("[MySQL] - Usuario actualizado correctamente: ID: %d Campos: %s", id, strings.Join(setParts, ", "))
 	} else {
-		log.Println("[MySQL] - No se eliminó ninguna fila")
+		log.Println("[MySQL] - No se actualizó ninguna fila")
 	}
 	return nil
 }
 
 func (mysql *MySQL) GetUserByCredentials(userName string) (*domain.User, error) {
-	query := "SELECT id, userName, email, password, estado, created_at FROM user WHERE userName = ?"
+	query := "SELECT " + userColumns + " FROM user WHERE userName = ?"
 	row, err := mysql.conn.FetchRow(query, userName)
 	if err != nil {
 		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
 	}
 
-	var user domain.User
-	err = row.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Estado, &user.CreatedAt)
+	user, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("usuario no encontrado")
+		return nil, fmt.Errorf("error al escanear el usuario: %v", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
-func (mysql *MySQL) ToggleUserStatus(id int32) (bool, error) {
-	// Primero obtenemos el estado actual
-	query := "SELECT estado FROM user WHERE id = ?"
-	row, err := mysql.conn.FetchRow(query, id)
+func (mysql *MySQL) GetByEmail(email string) (*domain.User, error) {
+	query := "SELECT " + userColumns + " FROM user WHERE email = ?"
+	row, err := mysql.conn.FetchRow(query, email)
 	if err != nil {
-		return false, fmt.Errorf("error al obtener el usuario: %v", err)
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
 	}
 
-	var currentStatus bool
-	err = row.Scan(&currentStatus)
+	user, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
 	if err != nil {
-		return false, fmt.Errorf("usuario no encontrado")
+		return nil, fmt.Errorf("error al escanear el usuario: %v", err)
 	}
 
-	// Cambiamos el estado
-	newStatus := !currentStatus
-	updateQuery := "UPDATE user SET estado = ? WHERE id = ?"
-	result, err := mysql.conn.ExecutePreparedQuery(updateQuery, newStatus, id)
-	if err != nil {
-		return false, fmt.Errorf("error al actualizar el estado: %v", err)
+	return user, nil
+}
+
+func (mysql *MySQL) MarkEmailVerified(id int32) error {
+	query := "UPDATE user SET email_verified_at = UTC_TIMESTAMP() WHERE id = ? AND email_verified_at IS NULL"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, id); err != nil {
+		return fmt.Errorf("error al verificar el email: %v", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 1 {
-		log.Printf("[MySQL] - Estado del usuario actualizado: ID: %d, Nuevo estado: %t", id, newStatus)
-		return newStatus, nil
+	log.Printf("[MySQL] - Email verificado para el usuario ID: %d", id)
+	return nil
+}
+
+// SetUserStatus activa o desactiva un usuario; devuelve false si ya tenía ese estado
+func (mysql *MySQL) SetUserStatus(id int32, estado bool, reason string, actorID int32) (bool, error) {
+	var query string
+	var args []interface{}
+	if estado {
+		// Las cuentas anonimizadas no pueden reactivarse
+		query = "UPDATE user SET estado = TRUE, deactivation_reason = NULL, deactivated_at = NULL, deactivated_by = NULL WHERE id = ? AND estado = FALSE AND anonymized_at IS NULL"
+		args = []interface{}{id}
 	} else {
-		return false, fmt.Errorf("no se actualizó ninguna fila")
+		query = "UPDATE user SET estado = FALSE, deactivation_reason = ?, deactivated_at = UTC_TIMESTAMP(), deactivated_by = ? WHERE id = ? AND estado = TRUE"
+		args = []interface{}{reason, actorID, id}
 	}
-}
 
-// ToggleUserStatusController maneja la solicitud HTTP para cambiar el estado de un usuario
-func (mysql *MySQL) ToggleUserStatusController(c *gin.Context) {
-	// Obtener el ID del usuario desde los parámetros de la URL
-	idParam := c.Param("id")
+	result, err := mysql.conn.ExecutePreparedQuery(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("error al actualizar el estado: %v", err)
+	}
 
-	// Convertir el ID a int32
-	var id int32
-	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
-		c.JSON(400, gin.H{
-			"error": "ID de usuario inválido",
-		})
-		return
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		// Distinguir entre usuario inexistente, cuenta anonimizada y estado sin cambios
+		var anonymized bool
+		err := mysql.conn.DB.QueryRow("SELECT anonymized_at IS NOT NULL FROM user WHERE id = ?", id).Scan(&anonymized)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, domain.ErrUserNotFound
+		}
+		if err != nil {
+			return false, fmt.Errorf("error al consultar el estado: %v", err)
+		}
+		if estado && anonymized {
+			return false, domain.ErrUserAnonymized
+		}
+		return false, nil
 	}
 
-	// Llamar al método para cambiar el estado
-	newStatus, err := mysql.ToggleUserStatus(id)
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error":   "Error al cambiar el estado del usuario",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	// Responder con el nuevo estado
-	c.JSON(200, gin.H{
-		"message":    "Estado del usuario actualizado correctamente",
-		"user_id":    id,
-		"new_status": newStatus,
-	})
+	log.Printf("[MySQL] - Estado del usuario actualizado: ID: %d, Nuevo estado: %t, Por: %d", id, estado, actorID)
+	return true, nil
 }
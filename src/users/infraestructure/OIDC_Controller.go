@@ -0,0 +1,155 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie que ata el state al navegador que inició el flujo
+const oidcStateCookie = "oidc_state"
+
+type OIDCController struct {
+	start    *application.StartOIDCLogin
+	complete *application.CompleteOIDCLogin
+	list     *application.ListIdentities
+	unlink   *application.UnlinkIdentity
+	// La cookie solo se envía a la ruta del callback y, con HTTPS, solo por conexiones seguras
+	cookiePath   string
+	cookieSecure bool
+}
+
+func NewOIDCController(start *application.StartOIDCLogin, complete *application.CompleteOIDCLogin, list *application.ListIdentities, unlink *application.UnlinkIdentity, redirectURL string) *OIDCController {
+	cookiePath := "/"
+	cookieSecure := false
+	if parsed, err := url.Parse(redirectURL); err == nil {
+		cookieSecure = parsed.Scheme == "https"
+		if parsed.Path != "" {
+			cookiePath = parsed.Path
+		}
+	}
+	return &OIDCController{start: start, complete: complete, list: list, unlink: unlink, cookiePath: cookiePath, cookieSecure: cookieSecure}
+}
+
+// Login redirige al proveedor para iniciar sesión con la identidad externa
+func (oc *OIDCController) Login(c *gin.Context) {
+	authorizationURL, binding, err := oc.start.Execute(0)
+	if err != nil {
+		oc.respondStartError(c, err)
+		return
+	}
+	oc.setStateCookie(c, binding)
+	c.Redirect(http.StatusFound, authorizationURL)
+}
+
+// Link devuelve la URL del proveedor para vincular una identidad a la cuenta autenticada.
+// No redirige porque el navegador no enviaría el access token al seguir la redirección.
+func (oc *OIDCController) Link(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	authorizationURL, binding, err := oc.start.Execute(user.ID)
+	if err != nil {
+		oc.respondStartError(c, err)
+		return
+	}
+	oc.setStateCookie(c, binding)
+	c.JSON(http.StatusOK, gin.H{"authorization_url": authorizationURL})
+}
+
+// Callback recibe al usuario de vuelta del proveedor con el código de autorización
+func (oc *OIDCController) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":    "El proveedor rechazó el inicio de sesión",
+			"code":     "OIDC_PROVIDER_ERROR",
+			"detalles": providerErr + " " + c.Query("error_description"),
+		})
+		return
+	}
+
+	// La cookie es de un solo uso, igual que el state
+	binding, _ := c.Cookie(oidcStateCookie)
+	oc.clearStateCookie(c)
+
+	result, err := oc.complete.Execute(c.Query("state"), binding, c.Query("code"), clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrOIDCProviderDisabled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrOIDCIdentityLinked):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrOIDCEmailConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": application.ErrOIDCEmailConflict.Message, "code": application.ErrOIDCEmailConflict.Code})
+		default:
+			respondAuthError(c, err)
+		}
+		return
+	}
+
+	if result.Linked {
+		c.JSON(http.StatusOK, gin.H{"message": "Identidad vinculada correctamente", "identity": result.Identity})
+		return
+	}
+	respondLoginResult(c, result.Login)
+}
+
+// ListMine devuelve las identidades externas vinculadas a la cuenta autenticada
+func (oc *OIDCController) ListMine(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	identities, err := oc.list.Execute(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las identidades", "detalles": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, identities)
+}
+
+// UnlinkMine desvincula una identidad externa de la cuenta autenticada
+func (oc *OIDCController) UnlinkMine(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("identityId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de identidad inválido"})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	if err := oc.unlink.Execute(user.ID, id); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrIdentityNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrOIDCLastSignInMethod):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al desvincular la identidad", "detalles": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Identidad desvinculada correctamente", "identity_id": id})
+}
+
+// setStateCookie guarda el vínculo del state; SameSite=Lax permite enviarla en la redirección
+// del proveedor (una navegación GET) pero no en peticiones iniciadas por otros sitios
+func (oc *OIDCController) setStateCookie(c *gin.Context, binding string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, binding, int(oc.start.TTL().Seconds()), oc.cookiePath, "", oc.cookieSecure, true)
+}
+
+func (oc *OIDCController) clearStateCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, oc.cookiePath, "", oc.cookieSecure, true)
+}
+
+func (oc *OIDCController) respondStartError(c *gin.Context, err error) {
+	if errors.Is(err, application.ErrOIDCProviderDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	// Normalmente el proveedor no respondió al descubrimiento
+	c.JSON(http.StatusBadGateway, gin.H{"error": "Error al contactar al proveedor de identidad", "detalles": err.Error()})
+}
@@ -0,0 +1,366 @@
+package infraestructure
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"expresApi/src/users/domain"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Margen de tolerancia para la diferencia de reloj con el proveedor
+const oidcClockSkew = time.Minute
+
+// OIDCProviderConfig es la configuración del cliente registrado en el proveedor
+type OIDCProviderConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCProvider habla con un proveedor OpenID Connect genérico a partir de su issuer:
+// la configuración se descubre en /.well-known/openid-configuration y las claves en su JWKS
+type OIDCProvider struct {
+	config OIDCProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+var _ domain.IIdentityProvider = (*OIDCProvider)(nil)
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// El issuer se guarda sin barra final para compararlo igual venga como venga del proveedor
+func NewOIDCProvider(config OIDCProviderConfig) *OIDCProvider {
+	config.IssuerURL = strings.TrimRight(config.IssuerURL, "/")
+	return &OIDCProvider{config: config, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *OIDCProvider) Issuer() string {
+	return p.config.IssuerURL
+}
+
+func (p *OIDCProvider) AuthorizationURL(state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (p *OIDCProvider) Exchange(code string, codeVerifier string) (*domain.ExternalIdentity, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &tokens); err != nil {
+		return nil, fmt.Errorf("error al canjear el código: %v", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("la respuesta del proveedor no incluye id_token")
+	}
+
+	return p.verifyIDToken(tokens.IDToken)
+}
+
+// idTokenClaims son los claims del ID token; aud puede ser un texto o una lista
+type idTokenClaims struct {
+	Issuer            string       `json:"iss"`
+	Subject           string       `json:"sub"`
+	Audience          oidcAudience `json:"aud"`
+	AuthorizedParty   string       `json:"azp"`
+	ExpiresAt         int64        `json:"exp"`
+	IssuedAt          int64        `json:"iat"`
+	Nonce             string       `json:"nonce"`
+	Email             string       `json:"email"`
+	EmailVerified     oidcBool     `json:"email_verified"`
+	PreferredUsername string       `json:"preferred_username"`
+	Name              string       `json:"name"`
+}
+
+type oidcAudience []string
+
+func (a *oidcAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = oidcAudience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// oidcBool acepta true/false y también "true"/"false", que algunos proveedores envían como texto
+type oidcBool bool
+
+func (b *oidcBool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		*b = oidcBool(value)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*b = oidcBool(text == "true")
+	return nil
+}
+
+// verifyIDToken comprueba la firma RS256 contra el JWKS y los claims iss, aud, azp, exp e iat
+func (p *OIDCProvider) verifyIDToken(token string) (*domain.ExternalIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("id_token mal formado")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("encabezado del id_token inválido")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, errors.New("encabezado del id_token inválido")
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("algoritmo de firma no soportado: %q", header.Alg)
+	}
+
+	key, err := p.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("firma del id_token inválida")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("firma del id_token inválida")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("claims del id_token inválidos")
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("claims del id_token inválidos")
+	}
+
+	now := time.Now()
+	switch {
+	case strings.TrimRight(claims.Issuer, "/") != p.config.IssuerURL:
+		return nil, fmt.Errorf("issuer inesperado: %q", claims.Issuer)
+	case claims.Subject == "":
+		return nil, errors.New("el id_token no incluye sub")
+	case !claims.Audience.contains(p.config.ClientID):
+		return nil, errors.New("el id_token no está emitido para este cliente")
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID:
+		return nil, errors.New("azp del id_token inválido")
+	case now.Add(-oidcClockSkew).Unix() >= claims.ExpiresAt:
+		return nil, errors.New("id_token expirado")
+	case claims.IssuedAt > now.Add(oidcClockSkew).Unix():
+		return nil, errors.New("id_token emitido en el futuro")
+	}
+
+	return &domain.ExternalIdentity{
+		Issuer:            p.config.IssuerURL,
+		Subject:           claims.Subject,
+		Email:             strings.TrimSpace(claims.Email),
+		EmailVerified:     bool(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+		Nonce:             claims.Nonce,
+	}, nil
+}
+
+func (a oidcAudience) contains(clientID string) bool {
+	for _, audience := range a {
+		if audience == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// discover obtiene y guarda en memoria el documento de descubrimiento del issuer
+func (p *OIDCProvider) discover() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.config.IssuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var discovery oidcDiscovery
+	if err := p.doJSON(req, &discovery); err != nil {
+		return nil, fmt.Errorf("error al descubrir el proveedor OIDC: %v", err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, fmt.Errorf("el proveedor anuncia el issuer %q en lugar de %q", discovery.Issuer, p.config.IssuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("el documento de descubrimiento está incompleto")
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key devuelve la clave pública del kid; si no la conoce vuelve a descargar el JWKS
+// para admitir la rotación de claves del proveedor
+func (p *OIDCProvider) key(kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, found := p.lookupKey(kid)
+	p.mu.Unlock()
+	if found {
+		return key, nil
+	}
+
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := p.fetchJWKS(discovery.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = keys
+	if key, found := p.lookupKey(kid); found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("clave de firma desconocida: %q", kid)
+}
+
+// lookupKey busca la clave por kid; sin kid solo se acepta si el JWKS tiene una única clave
+func (p *OIDCProvider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, found := p.keys[kid]
+	return key, found
+}
+
+func (p *OIDCProvider) fetchJWKS(jwksURI string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequest(http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.doJSON(req, &jwks); err != nil {
+		return nil, fmt.Errorf("error al obtener el JWKS: %v", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+	}
+	return keys, nil
+}
+
+// doJSON ejecuta la petición y decodifica la respuesta JSON; cualquier estado distinto de 200 es un error
+func (p *OIDCProvider) doJSON(req *http.Request, target interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s respondió %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, target)
+}
@@ -0,0 +1,67 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/users/application"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetController struct {
+	requestReset  *application.RequestPasswordReset
+	resetPassword *application.ResetPassword
+}
+
+func NewPasswordResetController(requestReset *application.RequestPasswordReset, resetPassword *application.ResetPassword) *PasswordResetController {
+	return &PasswordResetController{requestReset: requestReset, resetPassword: resetPassword}
+}
+
+type ForgotPasswordRequestBody struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequestBody struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+// Forgot solicita el enlace de restablecimiento; la respuesta es la misma exista o no el email
+func (pc *PasswordResetController) Forgot(c *gin.Context) {
+	var body ForgotPasswordRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	if err := pc.requestReset.Execute(body.Email); err != nil {
+		if errors.Is(err, application.ErrEmailRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al procesar la solicitud"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Si el email está registrado recibirás un enlace para restablecer tu contraseña"})
+}
+
+// Reset valida el token y guarda la nueva contraseña
+func (pc *PasswordResetController) Reset(c *gin.Context) {
+	var body ResetPasswordRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	if err := pc.resetPassword.Execute(body.Token, body.NewPassword); err != nil {
+		if errors.Is(err, application.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondUserError(c, err, "Error al restablecer la contraseña")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contraseña restablecida correctamente"})
+}
@@ -0,0 +1,90 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+)
+
+type MySQLPasswordReset struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IPasswordReset = (*MySQLPasswordReset)(nil)
+
+func NewMySQLPasswordReset(conn *config.Conn_MySQL) domain.IPasswordReset {
+	return &MySQLPasswordReset{conn: conn}
+}
+
+func (mysql *MySQLPasswordReset) Save(token *domain.PasswordResetToken) error {
+	query := "INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, UTC_TIMESTAMP())"
+	result, err := mysql.conn.ExecutePreparedQuery(query, token.UserID, token.TokenHash, token.ExpiresAt.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error al guardar el token de restablecimiento: %v", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		token.ID = id
+	}
+	return nil
+}
+
+func (mysql *MySQLPasswordReset) GetByHash(tokenHash string) (*domain.PasswordResetToken, error) {
+	query := "SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens WHERE token_hash = ?"
+	row, err := mysql.conn.FetchRow(query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var token domain.PasswordResetToken
+	var expiresAt, createdAt string
+	var usedAt sql.NullString
+	err = row.Scan(&token.ID, &token.UserID, &token.TokenHash, &expiresAt, &usedAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear el token de restablecimiento: %v", err)
+	}
+
+	token.ExpiresAt = parseDateTime(expiresAt)
+	token.CreatedAt = parseDateTime(createdAt)
+	token.UsedAt = parseNullDateTime(usedAt)
+	return &token, nil
+}
+
+// Redeem consume el token y cambia la contraseña juntos: si la actualización falla el token sigue siendo válido
+func (mysql *MySQLPasswordReset) Redeem(id int64, userID int32, hashedPassword string) (bool, error) {
+	tx, err := mysql.conn.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE password_reset_tokens SET used_at = UTC_TIMESTAMP() WHERE id = ? AND user_id = ? AND used_at IS NULL"
+	result, err := tx.Exec(query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("error al marcar el token como usado: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 1 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec("UPDATE user SET password = ? WHERE id = ?", hashedPassword, userID); err != nil {
+		return false, fmt.Errorf("error al actualizar la contraseña: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+	return true, nil
+}
+
+func (mysql *MySQLPasswordReset) InvalidateForUser(userID int32) error {
+	query := "UPDATE password_reset_tokens SET used_at = UTC_TIMESTAMP() WHERE user_id = ? AND used_at IS NULL"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, userID); err != nil {
+		return fmt.Errorf("error al invalidar los tokens de restablecimiento: %v", err)
+	}
+	return nil
+}
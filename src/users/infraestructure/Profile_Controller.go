@@ -0,0 +1,106 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/users/application"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileController struct {
+	update       *application.UpdateProfile
+	upload       *application.UploadAvatar
+	deleteAvatar *application.DeleteAvatar
+}
+
+func NewProfileController(update *application.UpdateProfile, upload *application.UploadAvatar, deleteAvatar *application.DeleteAvatar) *ProfileController {
+	return &ProfileController{update: update, upload: upload, deleteAvatar: deleteAvatar}
+}
+
+// Update modifica los campos del perfil presentes en el cuerpo; una cadena vacía borra el campo
+func (pc *ProfileController) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var body struct {
+		DisplayName *string `json:"display_name"`
+		Phone       *string `json:"phone"`
+		Locale      *string `json:"locale"`
+		Timezone    *string `json:"timezone"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer los datos", "detalles": err.Error()})
+		return
+	}
+
+	user, err := pc.update.Execute(int32(id), application.ProfileChanges{
+		DisplayName: body.DisplayName,
+		Phone:       body.Phone,
+		Locale:      body.Locale,
+		Timezone:    body.Timezone,
+	})
+	if err != nil {
+		respondUserError(c, err, "Error al actualizar el perfil")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Perfil actualizado correctamente", "user": user.Public()})
+}
+
+// UploadAvatar recibe la imagen en el campo "avatar" de un formulario multipart
+func (pc *ProfileController) UploadAvatar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	// Margen de 64 KB para los encabezados del formulario; el archivo se vuelve a medir después
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, pc.upload.MaxBytes()+64<<10)
+	file, _, err := c.Request.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondUserError(c, application.ErrAvatarTooLarge, "")
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falta el archivo en el campo avatar", "detalles": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, pc.upload.MaxBytes()+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el archivo", "detalles": err.Error()})
+		return
+	}
+
+	user, err := pc.upload.Execute(int32(id), content)
+	if err != nil {
+		respondUserError(c, err, "Error al guardar el avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Avatar actualizado correctamente", "user": user.Public()})
+}
+
+func (pc *ProfileController) DeleteAvatar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	if err := pc.deleteAvatar.Execute(int32(id)); err != nil {
+		respondUserError(c, err, "Error al eliminar el avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Avatar eliminado correctamente"})
+}
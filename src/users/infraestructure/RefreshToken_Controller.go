@@ -0,0 +1,52 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/users/application"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefreshTokenController struct {
+	useCase *application.RefreshSession
+}
+
+func NewRefreshTokenController(useCase *application.RefreshSession) *RefreshTokenController {
+	return &RefreshTokenController{useCase: useCase}
+}
+
+type RefreshRequestBody struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (rc *RefreshTokenController) Execute(c *gin.Context) {
+	var body RefreshRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil || body.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token es requerido"})
+		return
+	}
+
+	result, err := rc.useCase.Execute(body.RefreshToken, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidRefreshToken) || errors.Is(err, application.ErrRefreshTokenReused) || errors.Is(err, application.ErrSessionRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, application.ErrAccountDeactivated) {
+			respondAuthError(c, err)
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "No se pudo renovar la sesión", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":       result.AccessToken,
+		"token_type":         "Bearer",
+		"expires_at":         result.ExpiresAt.Format(time.RFC3339),
+		"refresh_token":      result.RefreshToken,
+		"refresh_expires_at": result.RefreshExpiresAt.Format(time.RFC3339),
+	})
+}
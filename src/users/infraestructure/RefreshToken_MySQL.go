@@ -0,0 +1,93 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+)
+
+type MySQLRefreshToken struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.IRefreshToken = (*MySQLRefreshToken)(nil)
+
+func NewMySQLRefreshToken(conn *config.Conn_MySQL) domain.IRefreshToken {
+	return &MySQLRefreshToken{conn: conn}
+}
+
+func (mysql *MySQLRefreshToken) Save(token *domain.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?, UTC_TIMESTAMP())"
+	result, err := mysql.conn.ExecutePreparedQuery(query, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error al guardar el refresh token: %v", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		token.ID = id
+	}
+	return nil
+}
+
+func (mysql *MySQLRefreshToken) GetByHash(tokenHash string) (*domain.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = ?`
+	row, err := mysql.conn.FetchRow(query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var token domain.RefreshToken
+	var expiresAt, createdAt string
+	var rotatedAt, revokedAt sql.NullString
+	err = row.Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &expiresAt, &rotatedAt, &revokedAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear el refresh token: %v", err)
+	}
+
+	token.ExpiresAt = parseDateTime(expiresAt)
+	token.CreatedAt = parseDateTime(createdAt)
+	token.RotatedAt = parseNullDateTime(rotatedAt)
+	token.RevokedAt = parseNullDateTime(revokedAt)
+	return &token, nil
+}
+
+func (mysql *MySQLRefreshToken) MarkRotated(id int64) (bool, error) {
+	query := "UPDATE refresh_tokens SET rotated_at = UTC_TIMESTAMP() WHERE id = ? AND rotated_at IS NULL AND revoked_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, id)
+	if err != nil {
+		return false, fmt.Errorf("error al rotar el refresh token: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (mysql *MySQLRefreshToken) RevokeFamily(familyID string) error {
+	query := "UPDATE refresh_tokens SET revoked_at = UTC_TIMESTAMP() WHERE family_id = ? AND revoked_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, familyID)
+	if err != nil {
+		return fmt.Errorf("error al revocar la familia de tokens: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("[MySQL] - Familia de refresh tokens revocada: %s (%d tokens)", familyID, rowsAffected)
+	return nil
+}
+
+func (mysql *MySQLRefreshToken) RevokeAllForUser(userID int32) error {
+	query := "UPDATE refresh_tokens SET revoked_at = UTC_TIMESTAMP() WHERE user_id = ? AND revoked_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, userID)
+	if err != nil {
+		return fmt.Errorf("error al revocar los refresh tokens: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("[MySQL] - Refresh tokens revocados para el usuario ID: %d (%d tokens)", userID, rowsAffected)
+	return nil
+}
@@ -0,0 +1,93 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RolesController struct {
+	listRoles       *application.ListRoles
+	getUserRoles    *application.GetUserRoles
+	updateUserRoles *application.UpdateUserRoles
+}
+
+func NewRolesController(listRoles *application.ListRoles, getUserRoles *application.GetUserRoles, updateUserRoles *application.UpdateUserRoles) *RolesController {
+	return &RolesController{
+		listRoles:       listRoles,
+		getUserRoles:    getUserRoles,
+		updateUserRoles: updateUserRoles,
+	}
+}
+
+type UpdateRolesRequestBody struct {
+	Roles []string `json:"roles"`
+}
+
+// ListRoles devuelve los roles disponibles con sus permisos
+func (rc *RolesController) ListRoles(c *gin.Context) {
+	roles, err := rc.listRoles.Execute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los roles", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetUserRoles devuelve los roles asignados a un usuario
+func (rc *RolesController) GetUserRoles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	roles, err := rc.getUserRoles.Execute(int32(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener los roles del usuario", "detalles": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": id, "roles": roles})
+}
+
+// UpdateUserRoles reemplaza los roles de un usuario
+func (rc *RolesController) UpdateUserRoles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var body UpdateRolesRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	actor, _ := middleware.CurrentUser(c)
+	roles, err := rc.updateUserRoles.Execute(actor.ID, int32(id), body.Roles)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrRoleNotFound), errors.Is(err, application.ErrSelfAdminRevoke):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar los roles", "detalles": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Roles actualizados correctamente", "user_id": id, "roles": roles})
+}
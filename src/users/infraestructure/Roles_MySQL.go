@@ -0,0 +1,135 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"strings"
+)
+
+func (mysql *MySQL) ListRoles() ([]domain.Role, error) {
+	query := `SELECT r.id, r.name, COALESCE(r.description, ''), COALESCE(GROUP_CONCAT(rp.permission ORDER BY rp.permission), '')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		GROUP BY r.id, r.name, r.description
+		ORDER BY r.name`
+	rows, err := mysql.conn.FetchRows(query)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los roles: %v", err)
+	}
+	defer rows.Close()
+
+	return scanRoles(rows)
+}
+
+func (mysql *MySQL) GetUserRoles(userID int32) ([]domain.Role, error) {
+	query := `SELECT r.id, r.name, COALESCE(r.description, ''), COALESCE(GROUP_CONCAT(rp.permission ORDER BY rp.permission), '')
+		FROM user_roles ur
+		JOIN roles r ON r.id = ur.role_id
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		WHERE ur.user_id = ?
+		GROUP BY r.id, r.name, r.description
+		ORDER BY r.name`
+	rows, err := mysql.conn.FetchRows(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los roles del usuario: %v", err)
+	}
+	defer rows.Close()
+
+	return scanRoles(rows)
+}
+
+func (mysql *MySQL) GetUserPermissions(userID int32) ([]string, error) {
+	query := `SELECT DISTINCT rp.permission
+		FROM user_roles ur
+		JOIN role_permissions rp ON rp.role_id = ur.role_id
+		WHERE ur.user_id = ?`
+	rows, err := mysql.conn.FetchRows(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los permisos del usuario: %v", err)
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("error al escanear el permiso: %v", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre los permisos: %v", err)
+	}
+	return permissions, nil
+}
+
+func (mysql *MySQL) AssignRole(userID int32, roleName string) error {
+	roleID, err := mysql.getRoleID(roleName)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT IGNORE INTO user_roles (user_id, role_id) VALUES (?, ?)"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, userID, roleID); err != nil {
+		return fmt.Errorf("error al asignar el rol: %v", err)
+	}
+
+	log.Printf("[MySQL] - Rol asignado: Usuario ID: %d Rol: %s", userID, roleName)
+	return nil
+}
+
+func (mysql *MySQL) RevokeRole(userID int32, roleName string) error {
+	roleID, err := mysql.getRoleID(roleName)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM user_roles WHERE user_id = ? AND role_id = ?"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, userID, roleID); err != nil {
+		return fmt.Errorf("error al revocar el rol: %v", err)
+	}
+
+	log.Printf("[MySQL] - Rol revocado: Usuario ID: %d Rol: %s", userID, roleName)
+	return nil
+}
+
+func (mysql *MySQL) getRoleID(roleName string) (int32, error) {
+	row, err := mysql.conn.FetchRow("SELECT id FROM roles WHERE name = ?", roleName)
+	if err != nil {
+		return 0, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var id int32
+	err = row.Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrRoleNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error al escanear el rol: %v", err)
+	}
+	return id, nil
+}
+
+func scanRoles(rows *sql.Rows) ([]domain.Role, error) {
+	roles := []domain.Role{}
+	for rows.Next() {
+		var role domain.Role
+		var permissions string
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &permissions); err != nil {
+			return nil, fmt.Errorf("error al escanear el rol: %v", err)
+		}
+		role.Permissions = []string{}
+		if permissions != "" {
+			role.Permissions = strings.Split(permissions, ",")
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre los roles: %v", err)
+	}
+	return roles, nil
+}
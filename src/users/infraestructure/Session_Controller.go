@@ -0,0 +1,125 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	sessions *application.SessionService
+	db       domain.IUser
+}
+
+func NewSessionController(sessions *application.SessionService, db domain.IUser) *SessionController {
+	return &SessionController{sessions: sessions, db: db}
+}
+
+// ListMine devuelve las sesiones activas del usuario autenticado
+func (sc *SessionController) ListMine(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	sessions, err := sc.sessions.List(user.ID, middleware.CurrentSessionID(c))
+	if err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeMine cierra una sesión del usuario autenticado
+func (sc *SessionController) RevokeMine(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	if err := sc.sessions.Revoke(user.ID, c.Param("sessionId")); err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada correctamente", "session_id": c.Param("sessionId")})
+}
+
+// RevokeOthers cierra todas las sesiones del usuario autenticado salvo la actual
+func (sc *SessionController) RevokeOthers(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	count, err := sc.sessions.RevokeOthers(user.ID, middleware.CurrentSessionID(c))
+	if err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Se cerraron las demás sesiones", "revoked": count})
+}
+
+// ListForUser devuelve las sesiones activas de cualquier usuario (administradores)
+func (sc *SessionController) ListForUser(c *gin.Context) {
+	id, ok := sc.userID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := sc.sessions.List(id, middleware.CurrentSessionID(c))
+	if err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeForUser cierra una sesión de cualquier usuario (administradores)
+func (sc *SessionController) RevokeForUser(c *gin.Context) {
+	id, ok := sc.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := sc.sessions.Revoke(id, c.Param("sessionId")); err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada correctamente", "user_id": id, "session_id": c.Param("sessionId")})
+}
+
+// RevokeAllForUser cierra todas las sesiones de un usuario (administradores)
+func (sc *SessionController) RevokeAllForUser(c *gin.Context) {
+	id, ok := sc.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := sc.sessions.RevokeAllForUser(id); err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Se cerraron todas las sesiones del usuario", "user_id": id})
+}
+
+// userID lee el parámetro :id y comprueba que el usuario exista
+func (sc *SessionController) userID(c *gin.Context) (int32, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return 0, false
+	}
+	if _, err := sc.db.GetByID(int32(id)); err != nil {
+		sc.respondError(c, err)
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func (sc *SessionController) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al gestionar las sesiones", "detalles": err.Error()})
+	}
+}
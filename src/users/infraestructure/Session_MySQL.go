@@ -0,0 +1,141 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+	"time"
+)
+
+type MySQLSession struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.ISession = (*MySQLSession)(nil)
+
+func NewMySQLSession(conn *config.Conn_MySQL) domain.ISession {
+	return &MySQLSession{conn: conn}
+}
+
+const sessionColumns = "id, user_id, user_agent, ip, created_at, last_seen_at, revoked_at"
+
+func scanSession(row rowScanner) (*domain.Session, error) {
+	var session domain.Session
+	var createdAt, lastSeenAt string
+	var revokedAt sql.NullString
+	err := row.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IP, &createdAt, &lastSeenAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	session.CreatedAt = parseDateTime(createdAt)
+	session.LastSeenAt = parseDateTime(lastSeenAt)
+	session.RevokedAt = parseNullDateTime(revokedAt)
+	return &session, nil
+}
+
+func (mysql *MySQLSession) Create(session *domain.Session) error {
+	query := "INSERT INTO user_sessions (id, user_id, user_agent, ip, created_at, last_seen_at) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := mysql.conn.ExecutePreparedQuery(query, session.ID, session.UserID, session.UserAgent, session.IP,
+		session.CreatedAt.UTC().Format(dateTimeLayout), session.LastSeenAt.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return fmt.Errorf("error al guardar la sesión: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQLSession) GetByID(id string) (*domain.Session, error) {
+	query := "SELECT " + sessionColumns + " FROM user_sessions WHERE id = ?"
+	row, err := mysql.conn.FetchRow(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	session, err := scanSession(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear la sesión: %v", err)
+	}
+	return session, nil
+}
+
+func (mysql *MySQLSession) ListActiveByUser(userID int32, since time.Time) ([]domain.Session, error) {
+	query := "SELECT " + sessionColumns + " FROM user_sessions WHERE user_id = ? AND revoked_at IS NULL AND last_seen_at >= ? ORDER BY last_seen_at DESC"
+	rows, err := mysql.conn.FetchRows(query, userID, since.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear la sesión: %v", err)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre las sesiones: %v", err)
+	}
+	return sessions, nil
+}
+
+func (mysql *MySQLSession) ListByUser(userID int32) ([]domain.Session, error) {
+	query := "SELECT " + sessionColumns + " FROM user_sessions WHERE user_id = ? ORDER BY created_at DESC"
+	rows, err := mysql.conn.FetchRows(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear la sesión: %v", err)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sobre las sesiones: %v", err)
+	}
+	return sessions, nil
+}
+
+func (mysql *MySQLSession) Touch(id string, ip string) error {
+	query := "UPDATE user_sessions SET last_seen_at = UTC_TIMESTAMP(), ip = ? WHERE id = ? AND revoked_at IS NULL"
+	if _, err := mysql.conn.ExecutePreparedQuery(query, ip, id); err != nil {
+		return fmt.Errorf("error al actualizar la sesión: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQLSession) Revoke(id string) (bool, error) {
+	query := "UPDATE user_sessions SET revoked_at = UTC_TIMESTAMP() WHERE id = ? AND revoked_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, id)
+	if err != nil {
+		return false, fmt.Errorf("error al revocar la sesión: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (mysql *MySQLSession) RevokeAllForUser(userID int32) error {
+	query := "UPDATE user_sessions SET revoked_at = UTC_TIMESTAMP() WHERE user_id = ? AND revoked_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, userID)
+	if err != nil {
+		return fmt.Errorf("error al revocar las sesiones: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	log.Printf("[MySQL] - Sesiones revocadas para el usuario ID: %d (%d sesiones)", userID, rowsAffected)
+	return nil
+}
@@ -0,0 +1,118 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	enroll   *application.EnrollTwoFactor
+	confirm  *application.ConfirmTwoFactor
+	complete *application.CompleteTwoFactorLogin
+	reset    *application.ResetTwoFactor
+}
+
+func NewTwoFactorController(enroll *application.EnrollTwoFactor, confirm *application.ConfirmTwoFactor, complete *application.CompleteTwoFactorLogin, reset *application.ResetTwoFactor) *TwoFactorController {
+	return &TwoFactorController{enroll: enroll, confirm: confirm, complete: complete, reset: reset}
+}
+
+type TwoFactorCodeBody struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorLoginBody struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// Enroll genera el secreto TOTP del usuario autenticado
+func (tc *TwoFactorController) Enroll(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+	secret, uri, err := tc.enroll.Execute(user.ID)
+	if err != nil {
+		tc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Escanea el código con tu aplicación de autenticación y confirma con un código",
+		"secret":      secret,
+		"otpauth_uri": uri,
+	})
+}
+
+// Confirm activa el 2FA tras validar el primer código
+func (tc *TwoFactorController) Confirm(c *gin.Context) {
+	var body TwoFactorCodeBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	codes, err := tc.confirm.Execute(user.ID, body.Code)
+	if err != nil {
+		tc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Autenticación en dos pasos activada. Guarda los códigos de recuperación, no se volverán a mostrar",
+		"recovery_codes": codes,
+	})
+}
+
+// Login completa un inicio de sesión que requiere el segundo paso
+func (tc *TwoFactorController) Login(c *gin.Context) {
+	var body TwoFactorLoginBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	result, err := tc.complete.Execute(body.Challenge, body.Code, clientInfo(c))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	respondLoginResult(c, result)
+}
+
+// Reset elimina el 2FA de un usuario (administradores)
+func (tc *TwoFactorController) Reset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	actor, _ := middleware.CurrentUser(c)
+	if err := tc.reset.Execute(actor.ID, int32(id)); err != nil {
+		tc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Autenticación en dos pasos restablecida", "user_id": id})
+}
+
+func (tc *TwoFactorController) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrTwoFactorAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrTwoFactorNotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": application.ErrInvalidTwoFactorCode.Message, "code": application.ErrInvalidTwoFactorCode.Code})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error en la autenticación en dos pasos", "detalles": err.Error()})
+	}
+}
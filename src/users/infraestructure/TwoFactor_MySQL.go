@@ -0,0 +1,109 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+)
+
+type MySQLTwoFactor struct {
+	conn *config.Conn_MySQL
+}
+
+var _ domain.ITwoFactor = (*MySQLTwoFactor)(nil)
+
+func NewMySQLTwoFactor(conn *config.Conn_MySQL) domain.ITwoFactor {
+	return &MySQLTwoFactor{conn: conn}
+}
+
+func (mysql *MySQLTwoFactor) GetByUserID(userID int32) (*domain.TwoFactor, error) {
+	query := "SELECT user_id, secret, enabled, last_step, confirmed_at FROM user_two_factor WHERE user_id = ?"
+	row, err := mysql.conn.FetchRow(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
+	}
+
+	var twoFactor domain.TwoFactor
+	var confirmedAt sql.NullString
+	err = row.Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.Enabled, &twoFactor.LastStep, &confirmedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear la configuración de dos pasos: %v", err)
+	}
+
+	twoFactor.ConfirmedAt = parseNullDateTime(confirmedAt)
+	return &twoFactor, nil
+}
+
+func (mysql *MySQLTwoFactor) SaveSecret(userID int32, secret string) error {
+	query := `INSERT INTO user_two_factor (user_id, secret, enabled, last_step, confirmed_at) VALUES (?, ?, FALSE, 0, NULL)
+		ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled = FALSE, last_step = 0, confirmed_at = NULL`
+	if _, err := mysql.conn.ExecutePreparedQuery(query, userID, secret); err != nil {
+		return fmt.Errorf("error al guardar el secreto de dos pasos: %v", err)
+	}
+	return nil
+}
+
+// Enable activa el 2FA y reemplaza los códigos de recuperación en una sola transacción
+func (mysql *MySQLTwoFactor) Enable(userID int32, recoveryCodeHashes []string) error {
+	tx, err := mysql.conn.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE user_two_factor SET enabled = TRUE, confirmed_at = UTC_TIMESTAMP() WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error al activar el 2FA: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM user_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error al eliminar los códigos de recuperación: %v", err)
+	}
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.Exec("INSERT INTO user_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, UTC_TIMESTAMP())", userID, hash); err != nil {
+			return fmt.Errorf("error al guardar el código de recuperación: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+	log.Printf("[MySQL] - 2FA activado para el usuario ID: %d", userID)
+	return nil
+}
+
+func (mysql *MySQLTwoFactor) Disable(userID int32) error {
+	if _, err := mysql.conn.ExecutePreparedQuery("DELETE FROM user_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error al eliminar los códigos de recuperación: %v", err)
+	}
+	if _, err := mysql.conn.ExecutePreparedQuery("DELETE FROM user_two_factor WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error al desactivar el 2FA: %v", err)
+	}
+	return nil
+}
+
+func (mysql *MySQLTwoFactor) UseStep(userID int32, step int64) (bool, error) {
+	// La condición last_step < ? evita que dos peticiones usen el mismo código
+	query := "UPDATE user_two_factor SET last_step = ? WHERE user_id = ? AND last_step < ?"
+	result, err := mysql.conn.ExecutePreparedQuery(query, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("error al registrar el paso TOTP: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (mysql *MySQLTwoFactor) UseRecoveryCode(userID int32, codeHash string) (bool, error) {
+	query := "UPDATE user_recovery_codes SET used_at = UTC_TIMESTAMP() WHERE user_id = ? AND code_hash = ? AND used_at IS NULL"
+	result, err := mysql.conn.ExecutePreparedQuery(query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("error al consumir el código de recuperación: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,224 @@
+package infraestructure
+
+import (
+	"encoding/csv"
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tamaño máximo del archivo CSV de importación
+const maxImportBytes = 5 << 20
+
+type UserCSVController struct {
+	importUsers *application.ImportUsers
+	exportUsers *application.ExportUsers
+}
+
+func NewUserCSVController(importUsers *application.ImportUsers, exportUsers *application.ExportUsers) *UserCSVController {
+	return &UserCSVController{importUsers: importUsers, exportUsers: exportUsers}
+}
+
+// Import crea usuarios desde un CSV con columnas username, email y opcionalmente role y estado.
+// Acepta el archivo en el campo "file" de un multipart o como cuerpo text/csv; ?dry_run=true solo valida.
+func (uc *UserCSVController) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	reader, closeReader, err := importSource(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el archivo CSV", "detalles": err.Error()})
+		return
+	}
+	defer closeReader()
+
+	rows, err := parseImportCSV(reader)
+	if errors.Is(err, application.ErrImportTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "max_rows": application.MaxImportRows})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV inválido", "detalles": err.Error()})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	actor, _ := middleware.CurrentUser(c)
+	canManageRoles := middleware.HasPermission(c, domain.PermissionRolesManage)
+
+	report, err := uc.importUsers.Execute(actor.ID, canManageRoles, rows, dryRun)
+	if err != nil {
+		if errors.Is(err, application.ErrImportTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "max_rows": application.MaxImportRows})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al importar los usuarios", "detalles": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+// Export descarga en CSV los usuarios que cumplen los mismos filtros que el listado
+func (uc *UserCSVController) Export(c *gin.Context) {
+	query, err := parseUserQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parámetros de búsqueda inválidos", "detalles": err.Error()})
+		return
+	}
+
+	writer := csv.NewWriter(c.Writer)
+	headerWritten := false
+	// La cabecera se escribe con el primer lote para poder responder JSON si la consulta falla antes
+	writeHeader := func() error {
+		filename := fmt.Sprintf("users-%s.csv", time.Now().Format("20060102-150405"))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Status(http.StatusOK)
+		headerWritten = true
+		return writer.Write([]string{"id", "username", "email", "estado", "email_verified", "created_at"})
+	}
+
+	err = uc.exportUsers.Execute(query, func(users []domain.User) error {
+		if !headerWritten {
+			if err := writeHeader(); err != nil {
+				return err
+			}
+		}
+
+		for _, user := range users {
+			record := []string{
+				strconv.Itoa(int(user.ID)),
+				csvSafe(user.UserName),
+				csvSafe(user.Email),
+				strconv.FormatBool(user.Estado),
+				strconv.FormatBool(user.EmailVerified),
+				user.CreatedAt,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		c.Writer.Flush()
+		return writer.Error()
+	})
+
+	if err != nil {
+		if headerWritten {
+			// La respuesta ya empezó; solo se puede cortar el archivo
+			c.Error(err)
+			return
+		}
+		if errors.Is(err, application.ErrInvalidUserSort) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al exportar los usuarios", "detalles": err.Error()})
+		return
+	}
+
+	// Sin resultados se devuelve un CSV con solo la cabecera
+	if !headerWritten {
+		writeHeader()
+		writer.Flush()
+	}
+}
+
+// importSource devuelve el CSV del campo "file" de un multipart o el cuerpo de la petición
+func importSource(c *gin.Context) (io.Reader, func(), error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, nil, err
+		}
+		opened, err := file.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		return opened, func() { opened.Close() }, nil
+	}
+	return c.Request.Body, func() {}, nil
+}
+
+// parseImportCSV lee el CSV usando la cabecera para ubicar las columnas
+func parseImportCSV(source io.Reader) ([]application.ImportUserRow, error) {
+	reader := csv.NewReader(source)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("el archivo está vacío")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// Quitar el BOM que agregan algunas hojas de cálculo
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("falta la columna %q en la cabecera", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return record[index]
+	}
+
+	rows := []application.ImportUserRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		rows = append(rows, application.ImportUserRow{
+			Line:     line,
+			UserName: field(record, "username"),
+			Email:    field(record, "email"),
+			Role:     field(record, "role"),
+			Estado:   field(record, "estado"),
+		})
+		if len(rows) > application.MaxImportRows {
+			return nil, application.ErrImportTooLarge
+		}
+	}
+	return rows, nil
+}
+
+// csvSafe evita que una hoja de cálculo interprete el valor como fórmula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"expresApi/src/config"
+	"expresApi/src/users/domain"
+	"fmt"
+	"log"
+)
+
+// MySQLUserErasure anonimiza la cuenta, sus comentarios y la auditoría en una misma transacción
+type MySQLUserErasure struct {
+	conn     *config.Conn_MySQL
+	comments *CommentRepositoryAdapter
+	audit    *MySQLAuditLog
+}
+
+var _ domain.IUserErasure = (*MySQLUserErasure)(nil)
+
+func NewMySQLUserErasure(conn *config.Conn_MySQL) domain.IUserErasure {
+	return &MySQLUserErasure{
+		conn:     conn,
+		comments: NewCommentRepositoryAdapter(conn.DB),
+		audit:    &MySQLAuditLog{conn: conn},
+	}
+}
+
+// sqlExecutor lo cumplen tanto *sql.DB como *sql.Tx
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Tablas con datos del usuario que se eliminan al anonimizarlo; la bitácora de auditoría se conserva
+var anonymizedUserTables = []string{
+	"user_roles",
+	"user_sessions",
+	"refresh_tokens",
+	"api_keys",
+	"user_recovery_codes",
+	"user_two_factor",
+	"password_reset_tokens",
+	"email_verification_tokens",
+	"user_identities",
+}
+
+func (mysql *MySQLUserErasure) Erase(erasure *domain.UserErasure) error {
+	tx, err := mysql.conn.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE user SET userName = ?, email = ?, password = ?, estado = FALSE, email_verified_at = NULL,
+		deactivation_reason = 'cuenta eliminada', deactivated_at = UTC_TIMESTAMP(), deactivated_by = NULL,
+		display_name = NULL, phone = NULL, locale = NULL, timezone = NULL, avatar_key = NULL, avatar_url = NULL,
+		anonymized_at = UTC_TIMESTAMP() WHERE id = ? AND anonymized_at IS NULL`
+	result, err := tx.Exec(query, erasure.UserName, erasure.Email, erasure.Password, erasure.UserID)
+	if err != nil {
+		return fmt.Errorf("error al anonimizar el usuario: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	for _, table := range anonymizedUserTables {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", erasure.UserID); err != nil {
+			return fmt.Errorf("error al eliminar los datos de %s: %v", table, err)
+		}
+	}
+
+	renamed, err := mysql.comments.ReplaceUserNameTx(tx, erasure.UserID, erasure.CommentAuthor)
+	if err != nil {
+		return err
+	}
+
+	erasure.Audit.Details = fmt.Sprintf("comentarios anonimizados: %d", renamed)
+	if err := mysql.audit.RecordTx(tx, erasure.Audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+	log.Printf("[MySQL] - Usuario anonimizado correctamente: ID: %d", erasure.UserID)
+	return nil
+}
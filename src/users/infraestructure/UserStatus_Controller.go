@@ -0,0 +1,90 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/config/middleware"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserStatusController struct {
+	activateUser   *application.ActivateUser
+	deactivateUser *application.DeactivateUser
+}
+
+func NewUserStatusController(activateUser *application.ActivateUser, deactivateUser *application.DeactivateUser) *UserStatusController {
+	return &UserStatusController{activateUser: activateUser, deactivateUser: deactivateUser}
+}
+
+type DeactivateRequestBody struct {
+	Reason string `json:"reason"`
+}
+
+// Activate activa la cuenta de un usuario
+func (sc *UserStatusController) Activate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	actor, _ := middleware.CurrentUser(c)
+	changed, err := sc.activateUser.Execute(actor.ID, int32(id))
+	if err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Usuario activado correctamente",
+		"user_id": id,
+		"estado":  true,
+		"changed": changed,
+	})
+}
+
+// Deactivate desactiva la cuenta de un usuario registrando el motivo
+func (sc *UserStatusController) Deactivate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var body DeactivateRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el JSON", "detalles": err.Error()})
+		return
+	}
+
+	actor, _ := middleware.CurrentUser(c)
+	changed, err := sc.deactivateUser.Execute(actor.ID, int32(id), body.Reason)
+	if err != nil {
+		sc.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Usuario desactivado correctamente",
+		"user_id": id,
+		"estado":  false,
+		"changed": changed,
+	})
+}
+
+func (sc *UserStatusController) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrUserAnonymized):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrSelfDeactivation), errors.Is(err, application.ErrDeactivationReasonMissing):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al cambiar el estado del usuario", "detalles": err.Error()})
+	}
+}
@@ -0,0 +1,37 @@
+package infraestructure
+
+import (
+	"errors"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondUserError traduce los errores de los casos de uso de usuarios a códigos HTTP
+func respondUserError(c *gin.Context, err error, message string) {
+	var policyErr *application.PasswordPolicyError
+	var profileErr *application.ProfileValidationError
+	switch {
+	case errors.As(err, &policyErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "La contraseña no cumple la política de seguridad", "fields": policyErr.Violations})
+	case errors.As(err, &profileErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "El perfil no es válido", "fields": profileErr.Violations})
+	case errors.Is(err, application.ErrAvatarTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrAvatarUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrAvatarInvalidImage), errors.Is(err, application.ErrAvatarTooManyPixels):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrUserNameTaken), errors.Is(err, domain.ErrEmailTaken), errors.Is(err, domain.ErrUserConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrUserNameRequired), errors.Is(err, application.ErrEmailRequired),
+		errors.Is(err, application.ErrPasswordRequired), errors.Is(err, application.ErrCurrentPasswordMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message, "detalles": err.Error()})
+	}
+}
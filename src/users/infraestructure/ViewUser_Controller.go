@@ -1,8 +1,13 @@
 package infraestructure
 
 import (
+	"errors"
 	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,12 +20,94 @@ func NewViewUserController(useCase *application.ViewUser) *ViewUserController {
 	return &ViewUserController{useCase: useCase}
 }
 
+// Execute lista usuarios con filtros, orden y paginación.
+// Parámetros: q, estado, created_from, created_to, sort, order (asc|desc), limit, offset, cursor
 func (eu_c *ViewUserController) Execute(c *gin.Context) {
-	user, err := eu_c.useCase.Execute()
+	query, err := parseUserQuery(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parámetros de búsqueda inválidos", "detalles": err.Error()})
+		return
+	}
+
+	page, err := eu_c.useCase.Execute(query, c.Query("cursor"))
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidUserSort) || errors.Is(err, application.ErrInvalidUserCursor) || errors.Is(err, application.ErrInvalidUserPage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{
+		"data":        domain.PublicUsers(page.Users),
+		"total":       page.Total,
+		"limit":       page.Limit,
+		"offset":      page.Offset,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+func parseUserQuery(c *gin.Context) (domain.UserQuery, error) {
+	query := domain.UserQuery{
+		Search: strings.TrimSpace(c.Query("q")),
+		SortBy: c.Query("sort"),
+	}
+
+	switch strings.ToLower(c.DefaultQuery("order", "asc")) {
+	case "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		return query, errors.New("order debe ser asc o desc")
+	}
+
+	if value := c.Query("estado"); value != "" {
+		estado, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, errors.New("estado debe ser true o false")
+		}
+		query.Estado = &estado
+	}
+
+	var err error
+	if query.CreatedFrom, err = parseDateParam(c.Query("created_from"), false); err != nil {
+		return query, errors.New("created_from inválido, usa YYYY-MM-DD o RFC3339")
+	}
+	if query.CreatedTo, err = parseDateParam(c.Query("created_to"), true); err != nil {
+		return query, errors.New("created_to inválido, usa YYYY-MM-DD o RFC3339")
+	}
+
+	if value := c.Query("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, errors.New("limit debe ser un número")
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		if query.Offset, err = strconv.Atoi(value); err != nil {
+			return query, errors.New("offset debe ser un número")
+		}
+	}
+	return query, nil
+}
+
+// parseDateParam acepta una fecha (YYYY-MM-DD) o una fecha y hora RFC3339.
+// Con endOfDay una fecha sin hora incluye el día completo. El resultado está en UTC,
+// como las columnas DATETIME, para que el desfase horario del cliente no se pierda.
+func parseDateParam(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		parsed = parsed.UTC()
+		return &parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Second)
+	}
+	return &parsed, nil
+}
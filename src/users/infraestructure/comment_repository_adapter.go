@@ -0,0 +1,45 @@
+package infraestructure
+
+import (
+	"database/sql"
+	commentDomain "expresApi/src/comments/domain"
+	commentInfra "expresApi/src/comments/infrastructure"
+	"expresApi/src/users/domain"
+)
+
+// CommentRepositoryAdapter adapta el repositorio de comentarios para las funciones de privacidad
+type CommentRepositoryAdapter struct {
+	repo commentDomain.CommentRepository
+}
+
+var _ domain.IUserComments = (*CommentRepositoryAdapter)(nil)
+
+// NewCommentRepositoryAdapter crea una nueva instancia del adaptador
+func NewCommentRepositoryAdapter(db *sql.DB) *CommentRepositoryAdapter {
+	return &CommentRepositoryAdapter{repo: commentInfra.NewMySQLCommentRepository(db)}
+}
+
+// ListByUserID obtiene los comentarios publicados por el usuario
+func (c *CommentRepositoryAdapter) ListByUserID(userID int32) ([]domain.UserComment, error) {
+	comments, err := c.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserComment, 0, len(comments))
+	for _, comment := range comments {
+		result = append(result, domain.UserComment{
+			ID:        comment.ID,
+			ProductID: comment.ProductID,
+			Comment:   comment.Comment,
+			Rating:    comment.Rating,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// ReplaceUserNameTx reescribe el autor de los comentarios del usuario dentro de la transacción tx
+func (c *CommentRepositoryAdapter) ReplaceUserNameTx(tx *sql.Tx, userID int32, replacement string) (int64, error) {
+	return commentInfra.ReplaceUserNameTx(tx, userID, replacement)
+}
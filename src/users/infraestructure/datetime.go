@@ -0,0 +1,25 @@
+package infraestructure
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Formato de fechas usado al leer y escribir columnas DATETIME (en UTC)
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+func parseDateTime(value string) time.Time {
+	parsed, err := time.Parse(dateTimeLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+func parseNullDateTime(value sql.NullString) *time.Time {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	parsed := parseDateTime(value.String)
+	return &parsed
+}
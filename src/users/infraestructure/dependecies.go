@@ -1,13 +1,249 @@
 package infraestructure
 
 import (
+	"expresApi/src/config"
+	"expresApi/src/users/application"
+	"expresApi/src/users/domain"
+	wsocket "expresApi/src/websocket"
 	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
 )
 
-func Init() {
-	mysqlRepo := NewMySQL()
+// UserDependencies contiene las dependencias compartidas del módulo de usuarios
+type UserDependencies struct {
+	Repository      domain.IUser
+	Tokens          *application.TokenService
+	RefreshTokens   *application.RefreshTokenService
+	LoginThrottle   *application.LoginThrottle
+	Attempts        domain.ILoginAttemptStore
+	PasswordPolicy  *application.PasswordPolicy
+	PasswordHashing *application.PasswordHashing
+	Resets          domain.IPasswordReset
+	Mailer          domain.Mailer
+	Verifications   domain.IEmailVerification
+	Verification    *application.EmailVerificationService
+	TwoFactor       domain.ITwoFactor
+	Sessions        *application.SessionService
+	Issuer          *application.SessionIssuer
+	ApiKeys         domain.IApiKey
+	Comments        domain.IUserComments
+	Audit           domain.IAuditLog
+	Erasure         domain.IUserErasure
+	Identities      domain.IUserIdentity
+	// IdentityProvider es nil si no se configuró OIDC_ISSUER
+	IdentityProvider domain.IIdentityProvider
+	Blobs            domain.IBlobStore
+	Auth             gin.HandlerFunc
+	WebSocketAuth    gin.HandlerFunc
+}
+
+// NewUserDependencies crea los repositorios, los servicios de tokens y el middleware de autenticación
+func NewUserDependencies() *UserDependencies {
+	conn := config.GetDBPool()
+	if conn.Err != "" {
+		log.Fatalf("Error al configurar el pool de conexiones: %v", conn.Err)
+	}
+
+	repository := &MySQL{conn: conn}
+	tokens := NewTokenService()
+	refreshTTL := config.GetEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour)
+	refreshTokens := application.NewRefreshTokenService(NewMySQLRefreshToken(conn), refreshTTL)
+
+	mailer := NewMailer()
+	verifications := NewMySQLEmailVerification(conn)
+	verification := application.NewEmailVerificationService(
+		verifications,
+		mailer,
+		config.GetEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		config.GetEnv("API_BASE_URL", "http://localhost:8080"),
+	)
+
+	sessions := application.NewSessionService(NewMySQLSession(conn), refreshTokens, wsocket.WSHub, refreshTTL)
+	apiKeys := NewMySQLApiKey(conn)
+	apiKeyAuth := application.NewApiKeyAuthenticator(apiKeys)
+	audit := NewMySQLAuditLog(conn)
+	impersonationAudit := application.NewImpersonationAudit(audit)
+	attempts := NewLoginAttemptStore(conn)
+
+	return &UserDependencies{
+		Repository:       repository,
+		Tokens:           tokens,
+		RefreshTokens:    refreshTokens,
+		LoginThrottle:    NewLoginThrottle(attempts),
+		Attempts:         attempts,
+		PasswordPolicy:   NewPasswordPolicy(),
+		PasswordHashing:  NewPasswordHashing(),
+		Resets:           NewMySQLPasswordReset(conn),
+		Mailer:           mailer,
+		Verifications:    verifications,
+		Verification:     verification,
+		TwoFactor:        NewMySQLTwoFactor(conn),
+		Sessions:         sessions,
+		Issuer:           application.NewSessionIssuer(tokens, refreshTokens, sessions),
+		ApiKeys:          apiKeys,
+		Comments:         NewCommentRepositoryAdapter(conn.DB),
+		Audit:            audit,
+		Erasure:          NewMySQLUserErasure(conn),
+		Identities:       NewMySQLUserIdentity(conn),
+		IdentityProvider: NewIdentityProvider(),
+		Blobs:            NewBlobStore(),
+		Auth:             NewAuthMiddleware(tokens, apiKeyAuth, sessions, repository, impersonationAudit),
+		WebSocketAuth:    NewWebSocketAuthMiddleware(tokens, apiKeyAuth, sessions, repository, impersonationAudit),
+	}
+}
+
+// NewLoginAttemptStore elige dónde se guardan los contadores de intentos.
+// LOGIN_ATTEMPT_STORE=mysql comparte los contadores entre instancias; por defecto se usa memoria.
+// LOGIN_ATTEMPT_TTL es cuánto se recuerda una clave sin actividad, incluido su historial de bloqueos.
+func NewLoginAttemptStore(conn *config.Conn_MySQL) domain.ILoginAttemptStore {
+	ttl := config.GetEnvDuration("LOGIN_ATTEMPT_TTL", 24*time.Hour)
+	if config.GetEnv("LOGIN_ATTEMPT_STORE", "memory") == "mysql" {
+		return NewMySQLLoginAttemptStore(conn, ttl)
+	}
+	return NewMemoryLoginAttemptStore(ttl)
+}
+
+// NewLoginThrottle configura la protección contra fuerza bruta desde el .env
+func NewLoginThrottle(store domain.ILoginAttemptStore) *application.LoginThrottle {
+	return application.NewLoginThrottle(store, application.LoginThrottlePolicy{
+		MaxUserFailures: config.GetEnvInt("LOGIN_MAX_FAILURES", 5),
+		MaxIPFailures:   config.GetEnvInt("LOGIN_MAX_IP_FAILURES", 20),
+		FailureWindow:   config.GetEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		BaseLockout:     config.GetEnvDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		MaxLockout:      config.GetEnvDuration("LOGIN_LOCKOUT_MAX", time.Hour),
+	})
+}
 
-	router := SetupRouter(mysqlRepo)
+// NewPasswordPolicy configura los requisitos de las contraseñas desde el .env.
+// PASSWORD_MAX_LENGTH nunca supera los 72 bytes que bcrypt tiene en cuenta.
+func NewPasswordPolicy() *application.PasswordPolicy {
+	maxBytes := config.GetEnvInt("PASSWORD_MAX_LENGTH", application.BcryptMaxPasswordBytes)
+	if maxBytes > application.BcryptMaxPasswordBytes {
+		log.Printf("[Config] - PASSWORD_MAX_LENGTH=%d supera el límite de bcrypt; se usa %d", maxBytes, application.BcryptMaxPasswordBytes)
+		maxBytes = application.BcryptMaxPasswordBytes
+	}
+
+	return &application.PasswordPolicy{
+		MinLength:            config.GetEnvInt("PASSWORD_MIN_LENGTH", 8),
+		MaxBytes:             maxBytes,
+		RequireUpper:         config.GetEnvBool("PASSWORD_REQUIRE_UPPER", true),
+		RequireLower:         config.GetEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		RequireDigit:         config.GetEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol:        config.GetEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		DisallowPersonalInfo: config.GetEnvBool("PASSWORD_DISALLOW_PERSONAL_INFO", true),
+		RejectCompromised:    config.GetEnvBool("PASSWORD_REJECT_COMPROMISED", true),
+	}
+}
+
+// NewPasswordHashing elige el algoritmo de los hashes nuevos con PASSWORD_HASHER (bcrypt | argon2id).
+// El otro algoritmo se sigue aceptando al verificar y sus hashes se regeneran en el siguiente login.
+func NewPasswordHashing() *application.PasswordHashing {
+	bcryptHasher := &application.BcryptHasher{
+		Cost: config.GetEnvInt("BCRYPT_COST", bcrypt.DefaultCost),
+	}
+	argon2Hasher := &application.Argon2idHasher{
+		Memory:      uint32(config.GetEnvInt("ARGON2_MEMORY_KB", 64*1024)),
+		Iterations:  uint32(config.GetEnvInt("ARGON2_ITERATIONS", 3)),
+		Parallelism: uint8(config.GetEnvInt("ARGON2_PARALLELISM", 2)),
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+
+	if bcryptHasher.Cost < bcrypt.MinCost || bcryptHasher.Cost > bcrypt.MaxCost {
+		log.Fatalf("Error al configurar las contraseñas: BCRYPT_COST debe estar entre %d y %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if argon2Hasher.Memory == 0 || argon2Hasher.Iterations == 0 || argon2Hasher.Parallelism == 0 {
+		log.Fatalf("Error al configurar las contraseñas: los parámetros de argon2id deben ser mayores que cero")
+	}
+	// Los hashes con parámetros por encima de estos límites se rechazan al verificar
+	if argon2Hasher.Memory > application.Argon2idMaxMemoryKB || argon2Hasher.Iterations > application.Argon2idMaxIterations ||
+		argon2Hasher.Memory < 8*uint32(argon2Hasher.Parallelism) {
+		log.Fatalf("Error al configurar las contraseñas: ARGON2_MEMORY_KB debe estar entre 8×ARGON2_PARALLELISM y %d y ARGON2_ITERATIONS no puede superar %d",
+			application.Argon2idMaxMemoryKB, application.Argon2idMaxIterations)
+	}
+
+	switch hasher := config.GetEnv("PASSWORD_HASHER", "bcrypt"); hasher {
+	case "bcrypt":
+		return application.NewPasswordHashing(bcryptHasher, argon2Hasher)
+	case "argon2id":
+		return application.NewPasswordHashing(argon2Hasher, bcryptHasher)
+	default:
+		log.Fatalf("Error al configurar las contraseñas: PASSWORD_HASHER=%q no es válido (bcrypt | argon2id)", hasher)
+		return nil
+	}
+}
+
+// NewIdentityProvider configura el proveedor OIDC a partir de su issuer (OIDC_ISSUER);
+// sin issuer el inicio de sesión externo queda desactivado
+func NewIdentityProvider() domain.IIdentityProvider {
+	issuer := config.GetEnv("OIDC_ISSUER", "")
+	if issuer == "" {
+		return nil
+	}
+
+	clientID := config.GetEnv("OIDC_CLIENT_ID", "")
+	redirectURL := config.GetEnv("OIDC_REDIRECT_URL", "")
+	if clientID == "" || redirectURL == "" {
+		log.Fatalf("Error al configurar OIDC: OIDC_CLIENT_ID y OIDC_REDIRECT_URL son requeridas junto con OIDC_ISSUER")
+	}
+
+	return NewOIDCProvider(OIDCProviderConfig{
+		IssuerURL:    issuer,
+		ClientID:     clientID,
+		ClientSecret: config.GetEnv("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  redirectURL,
+		Scopes:       strings.Fields(config.GetEnv("OIDC_SCOPES", "openid email profile")),
+	})
+}
+
+// NewLoginUser arma el caso de uso de login; TWO_FACTOR_CHALLENGE_TTL define la vigencia del desafío de 2FA
+func NewLoginUser(deps *UserDependencies) *application.LoginUser {
+	challengeTTL := config.GetEnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+	return application.NewLoginUser(deps.Repository, deps.Issuer, deps.LoginThrottle, deps.TwoFactor, deps.Tokens, deps.PasswordHashing, challengeTTL)
+}
+
+// NewTokenService configura el servicio de tokens con JWT_SECRET y JWT_ACCESS_TTL del .env
+func NewTokenService() *application.TokenService {
+	secret := config.GetEnv("JWT_SECRET", "")
+	if secret == "" {
+		log.Fatalf("Error al configurar JWT: la variable JWT_SECRET es requerida")
+	}
+	ttl := config.GetEnvDuration("JWT_ACCESS_TTL", 15*time.Minute)
+	return application.NewTokenService(secret, ttl)
+}
+
+// NewMailer elige la implementación de correo según MAIL_DRIVER (stdout | file)
+func NewMailer() domain.Mailer {
+	if config.GetEnv("MAIL_DRIVER", "stdout") == "file" {
+		return NewFileMailer(config.GetEnv("MAIL_DIR", "./mail"))
+	}
+	return NewStdoutMailer()
+}
+
+// NewBlobStore configura dónde se guardan los archivos subidos (BLOB_STORE=local).
+// UPLOADS_DIR es el directorio y UPLOADS_URL_PATH la ruta pública desde la que se sirven.
+func NewBlobStore() domain.IBlobStore {
+	switch driver := config.GetEnv("BLOB_STORE", "local"); driver {
+	case "local":
+		return NewLocalBlobStore(config.GetEnv("UPLOADS_DIR", "./uploads"), config.GetEnv("UPLOADS_URL_PATH", "/uploads"))
+	default:
+		log.Fatalf("Error al configurar los archivos: BLOB_STORE=%q no es válido (local)", driver)
+		return nil
+	}
+}
+
+// NewUploadAvatar arma el caso de uso de subida de avatares; AVATAR_MAX_BYTES limita el archivo original
+func NewUploadAvatar(deps *UserDependencies) *application.UploadAvatar {
+	maxBytes := int64(config.GetEnvInt("AVATAR_MAX_BYTES", 2<<20))
+	return application.NewUploadAvatar(deps.Repository, deps.Blobs, maxBytes)
+}
+
+func Init() {
+	router := SetupRouter(NewUserDependencies())
 	if err := router.Run(":8080"); err != nil {
 		log.Fatalf("Error al iniciar el servidor: %v", err)
 	}
@@ -1,65 +1,231 @@
 package infraestructure
 
 import (
+	"expresApi/src/config"
+	"expresApi/src/config/middleware"
 	"expresApi/src/users/application"
 	"expresApi/src/users/domain"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(repo domain.IUser) *gin.Engine {
+func SetupRouter(deps *UserDependencies) *gin.Engine {
 	r := gin.Default()
+	repo := deps.Repository
 
-	createUser := application.NewCreateUser(repo)
+	createUser := application.NewCreateUser(repo, deps.Verification, deps.PasswordPolicy, deps.PasswordHashing)
 	createUserController := NewCreateUserController(createUser)
 
 	viewUser := application.NewViewUser(repo)
 	viewUserController := NewViewUserController(viewUser)
 
-	editUserUseCase := application.NewEditUser(repo)
-	editUserController := NewEditUserController(editUserUseCase)
+	editUserUseCase := application.NewEditUser(repo, deps.Verification, deps.PasswordPolicy, deps.PasswordHashing, deps.Sessions)
+	patchUserUseCase := application.NewPatchUser(repo, deps.Verification)
+	editUserController := NewEditUserController(editUserUseCase, patchUserUseCase)
 
-	deleteUserUseCase := application.NewDeleteUser(repo)
+	getUserController := NewGetUserController(application.NewGetUser(repo))
+
+	deleteUserUseCase := application.NewDeleteUser(repo, deps.Erasure, deps.Sessions, deps.Blobs)
 	deleteUserController := NewDeleteUserController(deleteUserUseCase)
 
-	loginUser := application.NewLoginUser(repo)
+	loginUser := NewLoginUser(deps)
 	loginUserController := NewLoginUserController(loginUser)
 
 	r.POST("/user", createUserController.Execute)
-	r.GET("/user", viewUserController.Execute)
-	r.PUT("/user/:id", editUserController.Execute)
-	r.DELETE("/user/:id", deleteUserController.Execute)
+	r.GET("/user", deps.Auth, middleware.RequirePermission(domain.PermissionUsersRead), viewUserController.Execute)
+	r.GET("/user/:id", deps.Auth, middleware.RequireSelfOrPermission("id", domain.PermissionUsersRead), getUserController.Execute)
+	r.PUT("/user/:id", deps.Auth, middleware.RequireSelfOrPermission("id", domain.PermissionUsersWrite), editUserController.Execute)
+	r.DELETE("/user/:id", deps.Auth, middleware.RequirePermission(domain.PermissionUsersWrite), deleteUserController.Execute)
 	r.POST("/login", loginUserController.Execute)
+	RegisterStaticRoutes(r, deps)
 
 	return r
 }
 
+// RegisterStaticRoutes publica los archivos subidos cuando se guardan en el disco local
+func RegisterStaticRoutes(r gin.IRouter, deps *UserDependencies) {
+	if local, ok := deps.Blobs.(*LocalBlobStore); ok {
+		r.Group(local.URLPath(), staticFileHeaders).Static("/", local.Dir())
+	}
+}
+
+// staticFileHeaders quita el Content-Type JSON que fija el middleware CORS para que
+// http.ServeContent use el de la extensión del archivo, y evita que el navegador lo adivine
+func staticFileHeaders(c *gin.Context) {
+	c.Writer.Header().Del("Content-Type")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Next()
+}
+
 // Nueva función para registrar rutas en un grupo
-func RegisterRoutes(r *gin.RouterGroup, repo domain.IUser) {
-	// Crear instancia de MySQL para acceder al método ToggleUserStatusController
-	mysqlRepo := repo.(*MySQL)
+func RegisterRoutes(r *gin.RouterGroup, deps *UserDependencies) {
+	repo := deps.Repository
 
-	createUser := application.NewCreateUser(repo)
+	createUser := application.NewCreateUser(repo, deps.Verification, deps.PasswordPolicy, deps.PasswordHashing)
 	createUserController := NewCreateUserController(createUser)
 
 	viewUser := application.NewViewUser(repo)
 	viewUserController := NewViewUserController(viewUser)
 
-	editUserUseCase := application.NewEditUser(repo)
-	editUserController := NewEditUserController(editUserUseCase)
+	editUserUseCase := application.NewEditUser(repo, deps.Verification, deps.PasswordPolicy, deps.PasswordHashing, deps.Sessions)
+	patchUserUseCase := application.NewPatchUser(repo, deps.Verification)
+	editUserController := NewEditUserController(editUserUseCase, patchUserUseCase)
+
+	getUserController := NewGetUserController(application.NewGetUser(repo))
 
-	deleteUserUseCase := application.NewDeleteUser(repo)
+	deleteUserUseCase := application.NewDeleteUser(repo, deps.Erasure, deps.Sessions, deps.Blobs)
 	deleteUserController := NewDeleteUserController(deleteUserUseCase)
 
-	loginUser := application.NewLoginUser(repo)
+	loginUser := NewLoginUser(deps)
 	loginUserController := NewLoginUserController(loginUser)
 
+	refreshSession := application.NewRefreshSession(repo, deps.Tokens, deps.RefreshTokens, deps.Sessions)
+	refreshTokenController := NewRefreshTokenController(refreshSession)
+
+	logoutUser := application.NewLogoutUser(deps.Sessions)
+	logoutUserController := NewLogoutUserController(logoutUser)
+
+	changePassword := application.NewChangePassword(repo, deps.Sessions, deps.PasswordPolicy, deps.PasswordHashing)
+	changePasswordController := NewChangePasswordController(changePassword)
+
+	userStatusController := NewUserStatusController(
+		application.NewActivateUser(repo),
+		application.NewDeactivateUser(repo, deps.Sessions),
+	)
+
+	loginLockController := NewLoginLockController(deps.LoginThrottle)
+
+	emailVerificationController := NewEmailVerificationController(
+		application.NewVerifyEmail(deps.Verifications),
+		application.NewResendVerification(repo, deps.Attempts, deps.Verification, application.ResendPolicy{
+			MinInterval:     config.GetEnvDuration("EMAIL_VERIFICATION_RESEND_INTERVAL", time.Minute),
+			MaxPerHour:      config.GetEnvInt("EMAIL_VERIFICATION_RESEND_MAX_PER_HOUR", 5),
+			MaxPerHourPerIP: config.GetEnvInt("EMAIL_VERIFICATION_RESEND_MAX_PER_HOUR_IP", 20),
+		}),
+	)
+
+	requestPasswordReset := application.NewRequestPasswordReset(repo, deps.Resets, deps.Mailer, config.GetEnvDuration("PASSWORD_RESET_TTL", 30*time.Minute), config.GetEnv("APP_BASE_URL", "http://localhost:4200"))
+	passwordResetController := NewPasswordResetController(
+		requestPasswordReset,
+		application.NewResetPassword(repo, deps.Resets, deps.Sessions, deps.PasswordPolicy, deps.PasswordHashing),
+	)
+
+	twoFactorController := NewTwoFactorController(
+		application.NewEnrollTwoFactor(repo, deps.TwoFactor, config.GetEnv("TOTP_ISSUER", "expresApi")),
+		application.NewConfirmTwoFactor(deps.TwoFactor),
+		application.NewCompleteTwoFactorLogin(repo, deps.TwoFactor, deps.Tokens, deps.Issuer, deps.LoginThrottle),
+		application.NewResetTwoFactor(repo, deps.TwoFactor),
+	)
+
+	apiKeyController := NewApiKeyController(
+		application.NewCreateApiKey(repo, deps.ApiKeys),
+		application.NewListApiKeys(deps.ApiKeys),
+		application.NewRevokeApiKey(deps.ApiKeys),
+	)
+
+	sessionController := NewSessionController(deps.Sessions, repo)
+
+	exportUserDataController := NewExportUserDataController(
+		application.NewExportUserData(repo, deps.Comments, deps.Sessions, deps.ApiKeys, deps.TwoFactor, deps.Identities, deps.Audit),
+	)
+
+	userCSVController := NewUserCSVController(
+		application.NewImportUsers(repo, deps.Verification, requestPasswordReset),
+		application.NewExportUsers(repo),
+	)
+
+	rolesController := NewRolesController(
+		application.NewListRoles(repo),
+		application.NewGetUserRoles(repo),
+		application.NewUpdateUserRoles(repo),
+	)
+
+	oidcController := NewOIDCController(
+		application.NewStartOIDCLogin(deps.Identities, deps.IdentityProvider, config.GetEnvDuration("OIDC_STATE_TTL", 10*time.Minute)),
+		application.NewCompleteOIDCLogin(repo, deps.Identities, deps.IdentityProvider, loginUser, config.GetEnvBool("OIDC_AUTO_PROVISION", true)),
+		application.NewListIdentities(deps.Identities),
+		application.NewUnlinkIdentity(repo, deps.Identities, deps.PasswordHashing),
+		config.GetEnv("OIDC_REDIRECT_URL", ""),
+	)
+
+	impersonationController := NewImpersonationController(
+		application.NewImpersonateUser(repo, deps.Tokens, deps.Audit, config.GetEnvDuration("IMPERSONATION_TTL", 15*time.Minute)),
+	)
+
+	profileController := NewProfileController(
+		application.NewUpdateProfile(repo),
+		NewUploadAvatar(deps),
+		application.NewDeleteAvatar(repo, deps.Blobs),
+	)
+
+	canReadUsers := middleware.RequirePermission(domain.PermissionUsersRead)
+	canWriteUsers := middleware.RequirePermission(domain.PermissionUsersWrite)
+	canManageRoles := middleware.RequirePermission(domain.PermissionRolesManage)
+	selfOrReadUsers := middleware.RequireSelfOrPermission("id", domain.PermissionUsersRead)
+	selfOrWriteUsers := middleware.RequireSelfOrPermission("id", domain.PermissionUsersWrite)
+	// Las credenciales solo se gestionan con una sesión, no con una API key
+	sessionOnly := middleware.RequireSession()
+
 	r.POST("/users", createUserController.Execute)
-	r.GET("/users", viewUserController.Execute)
-	r.PUT("/users/:id", editUserController.Execute)
-	r.DELETE("/users/:id", deleteUserController.Execute)
+	r.GET("/users", deps.Auth, canReadUsers, viewUserController.Execute)
+	r.GET("/users/verify", emailVerificationController.Verify)
+	r.POST("/users/verify/resend", emailVerificationController.Resend)
+	r.GET("/users/me", deps.Auth, getUserController.Me)
+	r.POST("/users/import", deps.Auth, canWriteUsers, userCSVController.Import)
+	r.GET("/users/export", deps.Auth, canReadUsers, userCSVController.Export)
+	r.GET("/users/:id", deps.Auth, selfOrReadUsers, getUserController.Execute)
+
+	r.PUT("/users/:id", deps.Auth, selfOrWriteUsers, editUserController.Execute)
+	r.PATCH("/users/:id", deps.Auth, selfOrWriteUsers, editUserController.Patch)
+	r.PATCH("/users/:id/profile", deps.Auth, selfOrWriteUsers, profileController.Update)
+	r.PUT("/users/:id/avatar", deps.Auth, selfOrWriteUsers, profileController.UploadAvatar)
+	r.DELETE("/users/:id/avatar", deps.Auth, selfOrWriteUsers, profileController.DeleteAvatar)
+	r.PUT("/users/:id/password", deps.Auth, sessionOnly, selfOrWriteUsers, changePasswordController.Execute)
+	// Derecho al olvido y exportación de datos personales: el propio usuario o un administrador
+	r.DELETE("/users/:id", deps.Auth, sessionOnly, selfOrWriteUsers, deleteUserController.Execute)
+	r.GET("/users/:id/data-export", deps.Auth, selfOrReadUsers, exportUserDataController.Execute)
 	r.POST("/login", loginUserController.Execute)
-	r.PUT("/users/:id/toggle-status", mysqlRepo.ToggleUserStatusController)
-	// También agregar la función independiente como alternativa
-	r.PATCH("/users/:id/toggle-status", ToggleUserStatusController)
+	r.POST("/login/2fa", twoFactorController.Login)
+	r.POST("/token/refresh", refreshTokenController.Execute)
+	r.POST("/logout", deps.Auth, sessionOnly, logoutUserController.Execute)
+	r.POST("/users/:id/activate", deps.Auth, canWriteUsers, userStatusController.Activate)
+	r.POST("/users/:id/deactivate", deps.Auth, canWriteUsers, userStatusController.Deactivate)
+
+	r.POST("/password/forgot", passwordResetController.Forgot)
+	r.POST("/password/reset", passwordResetController.Reset)
+	r.DELETE("/login-locks", deps.Auth, canWriteUsers, loginLockController.Clear)
+
+	// Autenticación en dos pasos
+	r.POST("/2fa/enroll", deps.Auth, sessionOnly, twoFactorController.Enroll)
+	r.POST("/2fa/confirm", deps.Auth, sessionOnly, twoFactorController.Confirm)
+	r.DELETE("/users/:id/2fa", deps.Auth, canWriteUsers, twoFactorController.Reset)
+
+	// Sesiones activas: las propias y, para administradores, las de cualquier usuario
+	r.GET("/users/me/sessions", deps.Auth, sessionOnly, sessionController.ListMine)
+	r.DELETE("/users/me/sessions", deps.Auth, sessionOnly, sessionController.RevokeOthers)
+	r.DELETE("/users/me/sessions/:sessionId", deps.Auth, sessionOnly, sessionController.RevokeMine)
+	r.GET("/users/:id/sessions", deps.Auth, canReadUsers, sessionController.ListForUser)
+	r.DELETE("/users/:id/sessions", deps.Auth, canWriteUsers, sessionController.RevokeAllForUser)
+	r.DELETE("/users/:id/sessions/:sessionId", deps.Auth, canWriteUsers, sessionController.RevokeForUser)
+
+	// Inicio de sesión con un proveedor OpenID Connect e identidades vinculadas
+	r.GET("/oidc/login", oidcController.Login)
+	r.GET("/oidc/callback", oidcController.Callback)
+	r.POST("/oidc/link", deps.Auth, sessionOnly, oidcController.Link)
+	r.GET("/users/me/identities", deps.Auth, oidcController.ListMine)
+	r.DELETE("/users/me/identities/:identityId", deps.Auth, sessionOnly, oidcController.UnlinkMine)
+
+	// API keys del usuario autenticado
+	r.GET("/api-keys", deps.Auth, sessionOnly, apiKeyController.List)
+	r.POST("/api-keys", deps.Auth, sessionOnly, apiKeyController.Create)
+	r.DELETE("/api-keys/:id", deps.Auth, sessionOnly, apiKeyController.Revoke)
+
+	// Roles y permisos (solo administradores)
+	r.GET("/roles", deps.Auth, canManageRoles, rolesController.ListRoles)
+	r.GET("/users/:id/roles", deps.Auth, canManageRoles, rolesController.GetUserRoles)
+	r.PUT("/users/:id/roles", deps.Auth, canManageRoles, rolesController.UpdateUserRoles)
+
+	// Suplantación: solo desde una sesión de administrador; cada petición queda en la auditoría
+	r.POST("/users/:id/impersonate", deps.Auth, sessionOnly, middleware.RequirePermission(domain.PermissionUsersImpersonate), impersonationController.Execute)
 }
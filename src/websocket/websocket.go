@@ -1,6 +1,7 @@
 package wsocket
 
 import (
+	"expresApi/src/config/middleware"
 	"log"
 	"net/http"
 
@@ -18,28 +19,49 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-// Cliente representa una conexión WebSocket
+// Cliente representa una conexión WebSocket; userID y sessionID quedan vacíos en conexiones anónimas.
+// impersonatorID marca las conexiones abiertas por un administrador que suplanta al usuario.
+// permissions son los permisos del usuario al conectarse; las anónimas no tienen ninguno.
 type Client struct {
-	conn   *websocket.Conn
-	send   chan []byte
-	hub    *Hub
-	userID string
+	conn           *websocket.Conn
+	send           chan []byte
+	hub            *Hub
+	userID         int32
+	sessionID      string
+	impersonatorID int32
+	permissions    map[string]bool
+}
+
+// permissionMessage es un mensaje solo para los clientes con el permiso indicado
+type permissionMessage struct {
+	permission string
+	message    []byte
+}
+
+// disconnectRequest indica qué clientes deben desconectarse
+type disconnectRequest struct {
+	userID    int32
+	sessionID string
 }
 
 // Hub mantiene el conjunto de clientes activos y transmite mensajes
 type Hub struct {
 	clients    map[*Client]bool
 	broadcast  chan []byte
+	restricted chan permissionMessage
 	register   chan *Client
 	unregister chan *Client
+	disconnect chan disconnectRequest
 }
 
 // WSHub es la instancia global del hub
 var WSHub = &Hub{
 	clients:    make(map[*Client]bool),
 	broadcast:  make(chan []byte),
+	restricted: make(chan permissionMessage),
 	register:   make(chan *Client),
 	unregister: make(chan *Client),
+	disconnect: make(chan disconnectRequest),
 }
 
 // Run ejecuta el hub
@@ -57,19 +79,57 @@ func (h *Hub) Run() {
 				log.Printf("Cliente WebSocket desconectado. Total: %d", len(h.clients))
 			}
 
-		case message := <-h.broadcast:
+		case request := <-h.disconnect:
 			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
+				if client.matches(request) {
+					// Cerrar el canal hace que writePump envíe el mensaje de cierre
 					delete(h.clients, client)
+					close(client.send)
+				}
+			}
+
+		case message := <-h.broadcast:
+			for client := range h.clients {
+				h.deliver(client, message)
+			}
+
+		case restricted := <-h.restricted:
+			for client := range h.clients {
+				if client.permissions[restricted.permission] {
+					h.deliver(client, restricted.message)
 				}
 			}
 		}
 	}
 }
 
+// deliver encola el mensaje; un cliente que no lo consume a tiempo se desconecta
+func (h *Hub) deliver(client *Client, message []byte) {
+	select {
+	case client.send <- message:
+	default:
+		close(client.send)
+		delete(h.clients, client)
+	}
+}
+
+// DisconnectUser cierra todas las conexiones del usuario, incluidas las que abrió suplantando a otro
+func (h *Hub) DisconnectUser(userID int32) {
+	h.disconnect <- disconnectRequest{userID: userID}
+}
+
+// DisconnectSession cierra las conexiones abiertas con la sesión indicada
+func (h *Hub) DisconnectSession(sessionID string) {
+	h.disconnect <- disconnectRequest{sessionID: sessionID}
+}
+
+func (c *Client) matches(request disconnectRequest) bool {
+	if request.sessionID != "" {
+		return c.sessionID == request.sessionID
+	}
+	return request.userID != 0 && (c.userID == request.userID || c.impersonatorID == request.userID)
+}
+
 // readPump maneja los mensajes recibidos del cliente
 func (c *Client) readPump() {
 	defer func() {
@@ -108,7 +168,8 @@ func (c *Client) writePump() {
 	}
 }
 
-// HandleWebSocket maneja las conexiones WebSocket
+// HandleWebSocket maneja las conexiones WebSocket; si el middleware de autenticación
+// identificó al usuario, la conexión queda asociada a él y a su sesión
 func HandleWebSocket(c *gin.Context) {
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -121,6 +182,18 @@ func HandleWebSocket(c *gin.Context) {
 		send: make(chan []byte, 256),
 		hub:  WSHub,
 	}
+	if user, ok := middleware.CurrentUser(c); ok {
+		client.userID = user.ID
+		client.sessionID = middleware.CurrentSessionID(c)
+		client.permissions = make(map[string]bool)
+		for _, permission := range middleware.CurrentPermissions(c) {
+			client.permissions[permission] = true
+		}
+		if impersonatorID, ok := middleware.ImpersonatorID(c); ok {
+			client.impersonatorID = impersonatorID
+			log.Printf("[Impersonation] - Conexión WebSocket del usuario ID: %d abierta por el usuario ID: %d", user.ID, impersonatorID)
+		}
+	}
 
 	client.hub.register <- client
 
@@ -133,3 +206,8 @@ func HandleWebSocket(c *gin.Context) {
 func BroadcastMessage(message []byte) {
 	WSHub.broadcast <- message
 }
+
+// BroadcastToPermission envía un mensaje solo a los clientes autenticados que tienen el permiso
+func BroadcastToPermission(permission string, message []byte) {
+	WSHub.restricted <- permissionMessage{permission: permission, message: message}
+}